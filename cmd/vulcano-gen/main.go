@@ -0,0 +1,114 @@
+// Comando vulcano-gen: genera estructuras y repositorios Go a partir del esquema de la base de datos
+// configurada.
+//
+//	go run github.com/wfrscltech/vulcano/cmd/vulcano-gen -config config.json -schema dbo -out internal/models
+//
+// El archivo de configuración es el mismo del servicio; solo se usa la sección `database`.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/codegen"
+)
+
+func main() {
+	var (
+		cfgPath = flag.String("config", "config.json", "archivo de configuración con la sección `database`")
+		schema  = flag.String("schema", "", "esquema a generar; vacío para el esquema por defecto (PostgreSQL/SQL Server: todos)")
+		tables  = flag.String("tables", "", "tablas a generar separadas por coma; vacío para todas las del esquema")
+		out     = flag.String("out", "models", "directorio de salida")
+		pkg     = flag.String("package", "", "nombre del paquete; por defecto el nombre del directorio de salida")
+	)
+	flag.Parse()
+
+	if err := run(*cfgPath, *schema, *tables, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "vulcano-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgPath, schema, tableList, out, pkg string) error {
+	var cfg struct {
+		Database config.DatabaseConfig `json:"database"`
+	}
+	if err := config.ReadJSON(cfgPath, &cfg); err != nil {
+		return err
+	}
+	if err := cfg.Database.IsValid(); err != nil {
+		return err
+	}
+
+	if err := database.New(cfg.Database); err != nil {
+		return err
+	}
+	db := database.GetDatabase()
+	defer db.Close()
+
+	in, ok := db.(database.Inspector)
+	if !ok {
+		return fmt.Errorf("el motor `%s` no soporta la lectura del esquema", cfg.Database.Typo)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	tables, err := describe(ctx, in, schema, tableList)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return errors.New("no se encontraron tablas para generar")
+	}
+
+	if pkg == "" {
+		pkg = filepath.Base(out)
+	}
+	files, err := codegen.Generate(tables, codegen.Options{Package: pkg, Dialect: cfg.Database.Typo})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		path := filepath.Join(out, f.Name)
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+
+	return nil
+}
+
+// describe lee las tablas indicadas o, si no se indica ninguna, todas las del esquema
+func describe(ctx context.Context, in database.Inspector, schema, tableList string) ([]database.Table, error) {
+	if tableList == "" {
+		return database.DescribeSchema(ctx, in, schema)
+	}
+
+	var tables []database.Table
+	for name := range strings.SplitSeq(tableList, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		t, err := in.DescribeTable(ctx, schema, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		tables = append(tables, *t)
+	}
+	return tables, nil
+}
@@ -3,8 +3,34 @@ package config
 const (
 	DatabaseTypePostgres = "postgres"
 	DatabaseTypeMssql    = "mssql"
+	DatabaseTypeSqlite   = "sqlite"
+	DatabaseTypeMysql    = "mysql"
 )
 
-var supportedDatabaseTypes = []string{DatabaseTypePostgres, DatabaseTypeMssql}
+const (
+	TLSModeDisable    = "disable"
+	TLSModeRequire    = "require"
+	TLSModeSkipVerify = "skip-verify"
+	TLSModePreferred  = "preferred"
+)
+
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmES256 = "ES256"
+)
+
+var supportedDatabaseTypes = []string{
+	DatabaseTypePostgres,
+	DatabaseTypeMssql,
+	DatabaseTypeSqlite,
+	DatabaseTypeMysql,
+}
+
+var supportedTLSModes = []string{TLSModeDisable, TLSModeRequire, TLSModeSkipVerify, TLSModePreferred}
 
 var supportedLogLevels = []string{"debug", "info", "warning", "error"}
+
+var supportedCORSMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+var supportedJWTAlgorithms = []string{JWTAlgorithmHS256, JWTAlgorithmRS256, JWTAlgorithmES256}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/wfrscltech/vulcano/fn"
+)
+
+// CORSConfig define la política CORS del servidor HTTP. Los campos omitidos usan los valores por defecto
+// de infra/echo/middleware.CORSMiddleware
+type CORSConfig struct {
+	// Orígenes permitidos: `*` (cualquiera), un origen exacto (`https://app.example.com`) o los
+	// subdominios de un dominio (`https://*.example.com`)
+	AllowOrigins []string `json:"allowOrigins"`
+	// Métodos permitidos; por defecto GET, HEAD, POST, PUT, PATCH y DELETE
+	AllowMethods []string `json:"allowMethods,omitempty"`
+	// Encabezados permitidos en la petición; por defecto los que solicite el navegador
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+	// Encabezados de la respuesta visibles para el cliente; por defecto `ETag` y `X-Request-ID`
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+	// Permite enviar cookies y encabezados de autenticación; no se puede combinar con el origen `*`
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+	// Segundos que el navegador puede guardar la respuesta de verificación previa (preflight)
+	MaxAge int `json:"maxAge,omitempty"`
+}
+
+var (
+	corsOriginRe = regexp.MustCompile(`^https?://(\*\.)?([A-Za-z0-9-]+\.)*[A-Za-z0-9-]+(:[0-9]{1,5})?$`)
+	corsHeaderRe = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+)
+
+func (c *CORSConfig) IsValid() error {
+	if len(c.AllowOrigins) == 0 {
+		return errors.New("server.cors.allowOrigins: se debe indicar al menos un origen")
+	}
+
+	for _, o := range c.AllowOrigins {
+		if o == "*" {
+			if len(c.AllowOrigins) > 1 {
+				return errors.New("server.cors.allowOrigins: el origen `*` no se puede combinar con otros orígenes")
+			}
+			continue
+		}
+		if !corsOriginRe.MatchString(o) {
+			return fmt.Errorf(
+				"server.cors.allowOrigins: el origen `%s` no es válido. Use `*`, `https://app.example.com` o `https://*.example.com`, sin ruta ni barra final",
+				o,
+			)
+		}
+	}
+
+	for _, m := range c.AllowMethods {
+		if !fn.In(m, supportedCORSMethods...) {
+			return fmt.Errorf(
+				"server.cors.allowMethods: el valor `%s` no es un método válido. Las opciones válidas son: %q",
+				m,
+				supportedCORSMethods,
+			)
+		}
+	}
+
+	for _, h := range c.AllowHeaders {
+		if !corsHeaderRe.MatchString(h) {
+			return fmt.Errorf("server.cors.allowHeaders: el encabezado `%s` no es válido", h)
+		}
+	}
+	for _, h := range c.ExposeHeaders {
+		if !corsHeaderRe.MatchString(h) {
+			return fmt.Errorf("server.cors.exposeHeaders: el encabezado `%s` no es válido", h)
+		}
+	}
+
+	if c.MaxAge < 0 {
+		return errors.New("server.cors.maxAge: el valor no puede ser negativo")
+	}
+
+	// Con credenciales los navegadores no aceptan `*` como comodín (ver la especificación Fetch)
+	if c.AllowCredentials {
+		if slices.Contains(c.AllowOrigins, "*") {
+			return errors.New("server.cors.allowCredentials: no se puede combinar con el origen `*`; indique los orígenes permitidos")
+		}
+		if slices.Contains(c.AllowHeaders, "*") || slices.Contains(c.ExposeHeaders, "*") {
+			return errors.New("server.cors.allowCredentials: no se puede combinar con el encabezado `*`; indique los encabezados")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/wfrscltech/vulcano/fn"
+)
+
+// JWTConfig define la verificación de los tokens de acceso (ver infra/echo/auth)
+type JWTConfig struct {
+	// Algoritmos aceptados: HS256, RS256 y/o ES256
+	Algorithms []string `json:"algorithms"`
+	// Secreto compartido para HS256, de al menos 32 bytes
+	Secret string `json:"secret,omitempty"`
+	// Llave pública PEM (RSA o EC P-256) para RS256 o ES256
+	PublicKeyFile string `json:"publicKeyFile,omitempty"`
+	// Archivo JWKS local con las llaves públicas, elegidas por el `kid` del token. Se vuelve a leer cuando
+	// cambia, para rotar llaves sin reiniciar el servicio
+	JWKSFile string `json:"jwksFile,omitempty"`
+	// Segundos entre revisiones del archivo JWKS (por defecto 300)
+	JWKSRefresh int `json:"jwksRefresh,omitempty"`
+	// Emisor esperado (`iss`); vacío no lo valida
+	Issuer string `json:"issuer,omitempty"`
+	// Audiencia esperada (`aud`); vacío no la valida
+	Audience string `json:"audience,omitempty"`
+	// Segundos de tolerancia para `exp` y `nbf` por diferencias de reloj
+	Leeway int `json:"leeway,omitempty"`
+}
+
+func (j *JWTConfig) IsValid() error {
+	if len(j.Algorithms) == 0 {
+		return errors.New("jwt.algorithms: se debe indicar al menos un algoritmo")
+	}
+
+	for _, alg := range j.Algorithms {
+		if !fn.In(alg, supportedJWTAlgorithms...) {
+			return fmt.Errorf(
+				"jwt.algorithms: el valor `%s` no es un algoritmo válido. Las opciones válidas son: %q",
+				alg,
+				supportedJWTAlgorithms,
+			)
+		}
+	}
+
+	hmac := slices.Contains(j.Algorithms, JWTAlgorithmHS256)
+	if hmac && len(j.Secret) < 32 {
+		return errors.New("jwt.secret: HS256 requiere un secreto de al menos 32 bytes")
+	}
+	if !hmac && j.Secret != "" {
+		return errors.New("jwt.secret: el secreto solo se usa con HS256")
+	}
+
+	asymmetric := slices.Contains(j.Algorithms, JWTAlgorithmRS256) || slices.Contains(j.Algorithms, JWTAlgorithmES256)
+	if asymmetric && j.PublicKeyFile == "" && j.JWKSFile == "" {
+		return errors.New("jwt.*: RS256 y ES256 requieren `publicKeyFile` o `jwksFile`")
+	}
+
+	if j.JWKSRefresh < 0 {
+		return errors.New("jwt.jwksRefresh: el valor no puede ser negativo")
+	}
+
+	if j.Leeway < 0 {
+		return errors.New("jwt.leeway: el valor no puede ser negativo")
+	}
+
+	return nil
+}
@@ -13,6 +13,8 @@ type ServerConfig struct {
 	Port           int    `json:"port"`
 	LogLevel       string `json:"logLevel"`
 	LogDestination string `json:"logDestination"`
+	// Política CORS; si se omite se usa la política por defecto de NewEchoInstance
+	CORS *CORSConfig `json:"cors,omitempty"`
 }
 
 type DatabaseConfig struct {
@@ -22,14 +24,36 @@ type DatabaseConfig struct {
 	Password string `json:"password"`
 	Name     string `json:"name"`
 	Typo     string `json:"typo"`
+	// Modo TLS de la conexión (`disable`, `require`, `skip-verify` o `preferred`), usado por mysql
+	TLS string `json:"tls,omitempty"`
+	// Ruta opcional al certificado de autoridad (PEM) para validar el servidor cuando TLS está activo
+	TLSCAFile string `json:"tlsCAFile,omitempty"`
+	// Conexión diferida: el servicio inicia aunque la base de datos no esté disponible y se reintenta la
+	// conexión en segundo plano
+	Lazy bool `json:"lazy,omitempty"`
+	// Esquema de búsqueda (`search_path`) de las sesiones, usado por postgres para separar tenants por esquema
+	Schema string `json:"schema,omitempty"`
+	// Registra cada sentencia ejecutada en el nivel Debug, con el identificador de la petición que la originó
+	LogQueries bool `json:"logQueries,omitempty"`
 }
 
 type Config struct {
 	Server   ServerConfig   `json:"server"`
 	Database DatabaseConfig `json:"database"`
+	// Verificación de tokens de acceso; si se omite no se configura la autenticación
+	JWT *JWTConfig `json:"jwt,omitempty"`
 }
 
 func (d *DatabaseConfig) IsValid() error {
+	// SQLite es una base de datos embebida: solo requiere la ruta del archivo o `:memory:`
+	if d.Typo == DatabaseTypeSqlite {
+		if d.Name == "" {
+			return errors.New("database.name: el valor es obligatorio para sqlite (ruta del archivo o `:memory:`)")
+		}
+
+		return nil
+	}
+
 	if d.Host == "" || d.Port == 0 || d.User == "" || d.Password == "" || d.Name == "" {
 		return errors.New("database.*: todos los campos son obligatorios")
 	}
@@ -46,6 +70,18 @@ func (d *DatabaseConfig) IsValid() error {
 		)
 	}
 
+	if d.Schema != "" && d.Typo != DatabaseTypePostgres {
+		return errors.New("database.schema: el esquema de búsqueda solo se admite en postgres")
+	}
+
+	if d.TLS != "" && !fn.In(d.TLS, supportedTLSModes...) {
+		return fmt.Errorf(
+			"database.tls: el valor `%s` no es un modo TLS válido. Las opciones válidas son: %q",
+			d.TLS,
+			supportedTLSModes,
+		)
+	}
+
 	return nil
 }
 
@@ -75,6 +111,12 @@ func (s *ServerConfig) IsValid() error {
 		)
 	}
 
+	if s.CORS != nil {
+		if err := s.CORS.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -87,6 +129,12 @@ func (c *Config) IsValid() error {
 		return err
 	}
 
+	if c.JWT != nil {
+		if err := c.JWT.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -45,6 +45,20 @@ func TestDatabaseConfigIsValid_Success(t *testing.T) {
 				Typo:     DatabaseTypePostgres,
 			},
 		},
+		{
+			name: "Configuración válida con sqlite en archivo",
+			config: DatabaseConfig{
+				Name: "/var/lib/app/data.db",
+				Typo: DatabaseTypeSqlite,
+			},
+		},
+		{
+			name: "Configuración válida con sqlite en memoria",
+			config: DatabaseConfig{
+				Name: ":memory:",
+				Typo: DatabaseTypeSqlite,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -156,9 +170,9 @@ func TestDatabaseConfigIsValid_Failures(t *testing.T) {
 				User:     "admin",
 				Password: "secret",
 				Name:     "mydb",
-				Typo:     "mysql",
+				Typo:     "oracle",
 			},
-			expectedError: "database.typo: el valor `mysql` no es una base de datos válida",
+			expectedError: "database.typo: el valor `oracle` no es una base de datos válida",
 		},
 		{
 			name: "Tipo de base de datos vacío",
@@ -172,6 +186,15 @@ func TestDatabaseConfigIsValid_Failures(t *testing.T) {
 			},
 			expectedError: "database.typo: el valor `` no es una base de datos válida",
 		},
+		{
+			name: "Sqlite sin nombre de archivo",
+			config: DatabaseConfig{
+				Host: "localhost",
+				Port: 5432,
+				Typo: DatabaseTypeSqlite,
+			},
+			expectedError: "database.name: el valor es obligatorio para sqlite",
+		},
 	}
 
 	for _, tt := range tests {
@@ -761,6 +784,82 @@ func TestRead_EmptyObject(t *testing.T) {
 	}
 }
 
+// TestCORSConfigIsValid valida las políticas CORS, incluidas las que la especificación no permite
+func TestCORSConfigIsValid(t *testing.T) {
+	valid := []CORSConfig{
+		{AllowOrigins: []string{"*"}},
+		{AllowOrigins: []string{"https://app.example.com", "https://*.example.com", "http://localhost:5173"}, AllowCredentials: true},
+		{AllowOrigins: []string{"https://app.example.com"}, AllowMethods: []string{"GET", "DELETE"}, AllowHeaders: []string{"Authorization", "If-Match"}, ExposeHeaders: []string{"ETag"}, MaxAge: 600},
+	}
+	for _, c := range valid {
+		if err := c.IsValid(); err != nil {
+			t.Errorf("No se esperaba error para %+v, pero obtuvo: %v", c, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		config        CORSConfig
+		expectedError string
+	}{
+		{"Sin orígenes", CORSConfig{}, "server.cors.allowOrigins: se debe indicar al menos un origen"},
+		{"Origen con ruta", CORSConfig{AllowOrigins: []string{"https://app.example.com/"}}, "server.cors.allowOrigins: el origen `https://app.example.com/` no es válido"},
+		{"Comodín en medio del dominio", CORSConfig{AllowOrigins: []string{"https://app.*.com"}}, "server.cors.allowOrigins: el origen `https://app.*.com` no es válido"},
+		{"Comodín junto a otros orígenes", CORSConfig{AllowOrigins: []string{"*", "https://app.example.com"}}, "no se puede combinar con otros orígenes"},
+		{"Método inválido", CORSConfig{AllowOrigins: []string{"*"}, AllowMethods: []string{"get"}}, "server.cors.allowMethods: el valor `get` no es un método válido"},
+		{"Encabezado inválido", CORSConfig{AllowOrigins: []string{"*"}, ExposeHeaders: []string{"X Total"}}, "server.cors.exposeHeaders: el encabezado `X Total` no es válido"},
+		{"MaxAge negativo", CORSConfig{AllowOrigins: []string{"*"}, MaxAge: -1}, "server.cors.maxAge: el valor no puede ser negativo"},
+		{"Credenciales con origen comodín", CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true}, "server.cors.allowCredentials: no se puede combinar con el origen `*`"},
+		{"Credenciales con encabezado comodín", CORSConfig{AllowOrigins: []string{"https://app.example.com"}, AllowHeaders: []string{"*"}, AllowCredentials: true}, "server.cors.allowCredentials: no se puede combinar con el encabezado `*`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.IsValid()
+			if err == nil || !contains(err.Error(), tt.expectedError) {
+				t.Errorf("Error esperado que contenga %q, pero obtuvo: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+// TestJWTConfigIsValid valida la configuración de la verificación de tokens
+func TestJWTConfigIsValid(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	valid := []JWTConfig{
+		{Algorithms: []string{"HS256"}, Secret: secret, Issuer: "https://sso.example.com", Audience: "erp"},
+		{Algorithms: []string{"RS256", "ES256"}, JWKSFile: "/etc/app/jwks.json", JWKSRefresh: 60},
+		{Algorithms: []string{"HS256", "ES256"}, Secret: secret, PublicKeyFile: "/etc/app/public.pem", Leeway: 30},
+	}
+	for _, c := range valid {
+		if err := c.IsValid(); err != nil {
+			t.Errorf("No se esperaba error para %+v, pero obtuvo: %v", c, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		config        JWTConfig
+		expectedError string
+	}{
+		{"Sin algoritmos", JWTConfig{Secret: secret}, "jwt.algorithms: se debe indicar al menos un algoritmo"},
+		{"Algoritmo no soportado", JWTConfig{Algorithms: []string{"none"}}, "jwt.algorithms: el valor `none` no es un algoritmo válido"},
+		{"Secreto corto", JWTConfig{Algorithms: []string{"HS256"}, Secret: "corto"}, "jwt.secret: HS256 requiere un secreto de al menos 32 bytes"},
+		{"Secreto sin HS256", JWTConfig{Algorithms: []string{"RS256"}, Secret: secret, JWKSFile: "jwks.json"}, "jwt.secret: el secreto solo se usa con HS256"},
+		{"Asimétrico sin llave", JWTConfig{Algorithms: []string{"ES256"}}, "jwt.*: RS256 y ES256 requieren"},
+		{"Leeway negativo", JWTConfig{Algorithms: []string{"HS256"}, Secret: secret, Leeway: -1}, "jwt.leeway: el valor no puede ser negativo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.IsValid()
+			if err == nil || !contains(err.Error(), tt.expectedError) {
+				t.Errorf("Error esperado que contenga %q, pero obtuvo: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
 // contains es una función auxiliar para verificar si una cadena contiene otra
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
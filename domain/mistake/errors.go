@@ -29,16 +29,22 @@ const (
 	Invalid
 	Duplicated
 	Internal
+	Unavailable
+	PreconditionFailed
+	PreconditionRequired
 )
 
 var errorMessages = map[MistakeCode]int{
-	NotFound:     http.StatusNotFound,
-	Unauthorized: http.StatusUnauthorized,
-	Forbidden:    http.StatusForbidden,
-	Required:     http.StatusBadRequest,
-	Invalid:      http.StatusBadRequest,
-	Duplicated:   http.StatusConflict,
-	Internal:     http.StatusInternalServerError,
+	NotFound:             http.StatusNotFound,
+	Unauthorized:         http.StatusUnauthorized,
+	Forbidden:            http.StatusForbidden,
+	Required:             http.StatusBadRequest,
+	Invalid:              http.StatusBadRequest,
+	Duplicated:           http.StatusConflict,
+	Internal:             http.StatusInternalServerError,
+	Unavailable:          http.StatusServiceUnavailable,
+	PreconditionFailed:   http.StatusPreconditionFailed,
+	PreconditionRequired: http.StatusPreconditionRequired,
 }
 
 type Mistake struct {
@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"regexp"
+	"strings"
+)
+
+// Cantidad máxima de parámetros por sentencia INSERT en la carga por lotes genérica
+const bulkMaxParams = 900
+
+// identifierRe valida nombres de tablas y columnas para la carga masiva, que no admite parámetros
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// RowSource entrega las filas de una carga masiva una a una, sin necesidad de tenerlas todas en memoria.
+// Sigue el mismo contrato que pgx.CopyFromSource
+type RowSource interface {
+	// Next avanza a la siguiente fila; devuelve false al terminar o ante un error
+	Next() bool
+	// Values devuelve los valores de la fila actual, en el mismo orden que las columnas
+	Values() ([]any, error)
+	// Err devuelve el error que detuvo la iteración, si lo hubo
+	Err() error
+}
+
+// closeSource libera el origen si implementa Close (p. ej. RowsFromSeq). BulkInsert lo invoca al
+// terminar, también cuando la carga se interrumpe antes de agotar el origen
+func closeSource(src RowSource) {
+	if c, ok := src.(interface{ Close() }); ok {
+		c.Close()
+	}
+}
+
+// BulkOption configura una carga masiva
+type BulkOption func(*bulkOptions)
+
+type bulkOptions struct {
+	batchSize int
+	every     int64
+	progress  func(written int64)
+}
+
+// WithBatchSize indica la cantidad de filas por lote (SQL Server y carga genérica por INSERT)
+func WithBatchSize(n int) BulkOption {
+	return func(o *bulkOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithProgress invoca `fn` cada `every` filas enviadas y una última vez al terminar la carga
+func WithProgress(every int64, fn func(written int64)) BulkOption {
+	return func(o *bulkOptions) {
+		o.every = every
+		o.progress = fn
+	}
+}
+
+func newBulkOptions(opts []BulkOption) bulkOptions {
+	o := bulkOptions{every: 10000}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.every <= 0 {
+		o.every = 10000
+	}
+	return o
+}
+
+// RowsFromSlice crea un RowSource a partir de filas que ya están en memoria
+func RowsFromSlice(rows [][]any) RowSource {
+	return &sliceSource{rows: rows, pos: -1}
+}
+
+// RowsFromSeq crea un RowSource a partir de un iterador; la iteración se detiene en el primer error. Si la
+// carga termina antes de agotar el iterador, BulkInsert lo detiene con Close
+func RowsFromSeq(seq iter.Seq2[[]any, error]) RowSource {
+	next, stop := iter.Pull2(seq)
+	return &seqSource{next: next, stop: stop}
+}
+
+type sliceSource struct {
+	rows [][]any
+	pos  int
+}
+
+func (s *sliceSource) Next() bool {
+	s.pos++
+	return s.pos < len(s.rows)
+}
+
+func (s *sliceSource) Values() ([]any, error) {
+	return s.rows[s.pos], nil
+}
+
+func (s *sliceSource) Err() error {
+	return nil
+}
+
+type seqSource struct {
+	next func() ([]any, error, bool)
+	stop func()
+	row  []any
+	err  error
+}
+
+func (s *seqSource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	row, err, ok := s.next()
+	if !ok {
+		s.stop()
+		return false
+	}
+	if err != nil {
+		s.err = err
+		s.stop()
+		return false
+	}
+
+	s.row = row
+	return true
+}
+
+func (s *seqSource) Values() ([]any, error) {
+	return s.row, nil
+}
+
+func (s *seqSource) Err() error {
+	return s.err
+}
+
+// Close detiene el iterador; puede invocarse más de una vez
+func (s *seqSource) Close() {
+	s.stop()
+}
+
+// progressSource cuenta las filas entregadas por un RowSource y reporta el avance
+type progressSource struct {
+	RowSource
+	opts    bulkOptions
+	written int64
+}
+
+func (p *progressSource) Values() ([]any, error) {
+	v, err := p.RowSource.Values()
+	if err != nil {
+		return nil, err
+	}
+
+	p.written++
+	if p.opts.progress != nil && p.written%p.opts.every == 0 {
+		p.opts.progress(p.written)
+	}
+	return v, nil
+}
+
+// done reporta el avance final de la carga
+func (p *progressSource) done() {
+	if p.opts.progress != nil && p.written%p.opts.every != 0 {
+		p.opts.progress(p.written)
+	}
+}
+
+// validateBulkTarget valida la tabla y las columnas de una carga masiva
+func validateBulkTarget(table string, columns []string) error {
+	if !identifierRe.MatchString(table) {
+		return fmt.Errorf("carga masiva: el nombre de tabla `%s` no es válido", table)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("carga masiva: se requiere al menos una columna para la tabla `%s`", table)
+	}
+
+	for _, c := range columns {
+		if !identifierRe.MatchString(c) {
+			return fmt.Errorf("carga masiva: el nombre de columna `%s` no es válido", c)
+		}
+	}
+
+	return nil
+}
+
+// execer es la parte común de *sql.DB y *sql.Tx usada por la carga genérica
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertBatches carga las filas mediante sentencias INSERT de varias filas con marcadores `?`. Es la
+// estrategia genérica para los motores sin un protocolo de carga masiva (SQLite, MySQL/MariaDB)
+func insertBatches(ctx context.Context, db execer, table string, columns []string, src RowSource, opts bulkOptions) (int64, error) {
+	batch := bulkMaxParams / len(columns)
+	if opts.batchSize > 0 && opts.batchSize < batch {
+		batch = opts.batchSize
+	}
+	if batch < 1 {
+		batch = 1
+	}
+
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+
+	ps := &progressSource{RowSource: src, opts: opts}
+	args := make([]any, 0, batch*len(columns))
+	pending := 0
+	var total int64
+
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		query := prefix + strings.TrimSuffix(strings.Repeat(row+", ", pending), ", ")
+		res, err := db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		total += n
+		args = args[:0]
+		pending = 0
+		return nil
+	}
+
+	for ps.Next() {
+		values, err := ps.Values()
+		if err != nil {
+			return total, err
+		}
+		if len(values) != len(columns) {
+			return total, fmt.Errorf("carga masiva: se esperaban %d valores por fila, se recibieron %d", len(columns), len(values))
+		}
+
+		args = append(args, values...)
+		pending++
+		if pending == batch {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := ps.Err(); err != nil {
+		return total, err
+	}
+	if err := flush(); err != nil {
+		return total, err
+	}
+
+	ps.done()
+	return total, nil
+}
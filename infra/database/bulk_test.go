@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// TestBulkInsert_Batches valida la carga genérica por lotes y el reporte de avance
+func TestBulkInsert_Batches(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE ventas (id INTEGER, producto TEXT)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+
+	seq := func(yield func([]any, error) bool) {
+		for i := range 1050 {
+			if !yield([]any{i, fmt.Sprintf("P-%d", i)}, nil) {
+				return
+			}
+		}
+	}
+
+	var progress []int64
+	n, err := db.BulkInsert(ctx, "ventas", []string{"id", "producto"}, RowsFromSeq(seq),
+		WithBatchSize(100),
+		WithProgress(500, func(written int64) { progress = append(progress, written) }),
+	)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if n != 1050 {
+		t.Errorf("Se esperaban 1050 filas escritas, obtuvo: %d", n)
+	}
+	if fmt.Sprint(progress) != "[500 1000 1050]" {
+		t.Errorf("Avance inesperado: %v", progress)
+	}
+
+	var total int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM ventas").Scan(&total); err != nil || total != 1050 {
+		t.Errorf("Se esperaban 1050 registros, obtuvo: %d (%v)", total, err)
+	}
+}
+
+// TestBulkInsert_SourceError valida que un error del origen revierta la carga completa
+func TestBulkInsert_SourceError(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE ventas (id INTEGER)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+
+	boom := errors.New("fallo de lectura")
+	var seq iter.Seq2[[]any, error] = func(yield func([]any, error) bool) {
+		if yield([]any{1}, nil) {
+			yield(nil, boom)
+		}
+	}
+
+	if _, err := db.BulkInsert(ctx, "ventas", []string{"id"}, RowsFromSeq(seq), WithBatchSize(1)); !errors.Is(err, boom) {
+		t.Errorf("Se esperaba el error del origen, obtuvo: %v", err)
+	}
+
+	var total int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM ventas").Scan(&total); err != nil || total != 0 {
+		t.Errorf("Se esperaban 0 registros tras el error, obtuvo: %d (%v)", total, err)
+	}
+
+	if _, err := db.BulkInsert(ctx, "ventas; DROP TABLE ventas", []string{"id"}, RowsFromSlice(nil)); err == nil {
+		t.Error("Se esperaba un error por nombre de tabla inválido")
+	}
+}
+
+// TestBulkInsert_StopsSeq valida que el iterador se detenga cuando la carga falla antes de agotarlo
+func TestBulkInsert_StopsSeq(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE ventas (id INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+
+	var yielded int
+	stopped := false
+	var seq iter.Seq2[[]any, error] = func(yield func([]any, error) bool) {
+		defer func() { stopped = true }()
+		for i := range 100 {
+			yielded++
+			row := []any{i}
+			if i == 1 {
+				row = []any{nil} // viola NOT NULL
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+
+	if _, err := db.BulkInsert(ctx, "ventas", []string{"id"}, RowsFromSeq(seq), WithBatchSize(1)); err == nil {
+		t.Fatal("Se esperaba el error de la inserción")
+	}
+	if !stopped {
+		t.Error("Se esperaba que el iterador se detuviera al fallar la carga")
+	}
+	if yielded == 100 {
+		t.Errorf("Se esperaba que la carga se interrumpiera, pero el iterador entregó %d filas", yielded)
+	}
+}
@@ -0,0 +1,321 @@
+// Package codegen genera estructuras y repositorios Go a partir del esquema leído con
+// database.Inspector. Lo usa el comando `cmd/vulcano-gen`, pero también se puede invocar desde otras
+// herramientas:
+//
+//	tables, err := database.DescribeSchema(ctx, inspector, "dbo")
+//	...
+//	files, err := codegen.Generate(tables, codegen.Options{Package: "models", Dialect: "mssql"})
+package codegen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+//go:embed repository.go.tmpl
+var templates embed.FS
+
+var repoTmpl = template.Must(template.New("repository.go.tmpl").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"sql":   sqlLiteral,
+}).ParseFS(templates, "repository.go.tmpl"))
+
+// Options configura la generación
+type Options struct {
+	// Nombre del paquete Go de los archivos generados
+	Package string
+	// Motor de base de datos (valor de config.DatabaseConfig.Typo); define los marcadores de parámetros,
+	// el quoting de identificadores y la paginación
+	Dialect string
+}
+
+// File es un archivo generado
+type File struct {
+	// Nombre del archivo (ej. `clientes.go`)
+	Name string
+	// Código Go formateado
+	Content []byte
+}
+
+// Generate genera un archivo por tabla con su estructura y su repositorio
+func Generate(tables []database.Table, opts Options) ([]File, error) {
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("codegen: el nombre de paquete `%s` no es válido", opts.Package)
+	}
+	if !slices.Contains([]string{config.DatabaseTypePostgres, config.DatabaseTypeMssql, config.DatabaseTypeSqlite, config.DatabaseTypeMysql}, opts.Dialect) {
+		return nil, fmt.Errorf("codegen: el dialecto `%s` no es válido", opts.Dialect)
+	}
+
+	files := make([]File, 0, len(tables))
+	for _, t := range tables {
+		content, err := GenerateTable(t, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: fileName(t), Content: content})
+	}
+	return files, nil
+}
+
+// GenerateTable genera el código de una tabla
+func GenerateTable(t database.Table, opts Options) ([]byte, error) {
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("codegen: la tabla `%s` no tiene columnas", t.Name)
+	}
+
+	data := newTableData(t, opts)
+
+	var buf bytes.Buffer
+	if err := repoTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("codegen: %s: %w", t.Name, err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: %s: el código generado no es válido: %w", t.Name, err)
+	}
+	return src, nil
+}
+
+// field es una columna de la tabla como campo de la estructura
+type field struct {
+	Name     string
+	Type     string
+	Column   string
+	DBType   string
+	Nullable bool
+	// Nombre del parámetro en los métodos que reciben la llave primaria
+	Param string
+}
+
+// tableData contiene lo necesario para generar el archivo de una tabla
+type tableData struct {
+	Package string
+	// Importaciones de la biblioteca estándar y de terceros, ya escritas como en el código
+	Std      []string
+	Imports  []string
+	Type     string
+	FullName string
+	IsView   bool
+	Fields   []field
+	PK       []field
+	Identity []field
+	Insert   []field
+	Update   []field
+
+	SelectSQL string
+	GetSQL    string
+	ListSQL   string
+	// Orden de los argumentos de ListSQL: `limit, offset` o `offset, limit`
+	ListArgs  string
+	InsertSQL string
+	// Indica si InsertSQL devuelve las columnas IDENTITY
+	Returning bool
+	UpdateSQL string
+	DeleteSQL string
+}
+
+func newTableData(t database.Table, opts Options) tableData {
+	d := tableData{
+		Package:  opts.Package,
+		Type:     goName(t.Name),
+		FullName: t.Name,
+		IsView:   t.Kind == database.KindView,
+	}
+	if t.Schema != "" && opts.Dialect != config.DatabaseTypeSqlite {
+		d.FullName = t.Schema + "." + t.Name
+	}
+
+	imports := map[string]bool{`"context"`: true, `"github.com/wfrscltech/vulcano/infra/database"`: true}
+	byName := map[string]field{}
+	used := map[string]bool{"ctx": true, "r": true, "v": true, "limit": true, "offset": true}
+	for _, c := range t.Columns {
+		gt := columnType(opts.Dialect, c)
+		if gt.pkg != "" {
+			imports[gt.pkg] = true
+		}
+		f := field{Name: goName(c.Name), Type: gt.name, Column: c.Name, DBType: c.DatabaseType, Nullable: c.Nullable}
+		f.Param = paramName(f.Name, used)
+		d.Fields = append(d.Fields, f)
+		byName[c.Name] = f
+		if c.Identity {
+			d.Identity = append(d.Identity, f)
+		}
+	}
+	for _, name := range t.PrimaryKey {
+		d.PK = append(d.PK, byName[name])
+	}
+	for _, f := range d.Fields {
+		if !containsField(d.Identity, f) {
+			d.Insert = append(d.Insert, f)
+			if !containsField(d.PK, f) {
+				d.Update = append(d.Update, f)
+			}
+		}
+	}
+
+	s := sqlBuilder{dialect: opts.Dialect}
+	table := s.table(t)
+
+	d.SelectSQL = "SELECT " + s.columns(d.Fields, "") + " FROM " + table
+
+	order := d.PK
+	if len(order) == 0 {
+		order = d.Fields[:1]
+	}
+	d.ListSQL, d.ListArgs = s.list(d.SelectSQL, order)
+
+	if d.IsView {
+		d.PK, d.Identity, d.Insert, d.Update = nil, nil, nil, nil
+	} else {
+		imports[`"github.com/wfrscltech/vulcano/domain/mistake"`] = len(d.PK) > 0
+		if len(d.PK) > 0 {
+			d.GetSQL = d.SelectSQL + " WHERE " + s.conditions(d.PK, 1)
+			d.DeleteSQL = "DELETE FROM " + table + " WHERE " + s.conditions(d.PK, 1)
+			if len(d.Update) > 0 {
+				d.UpdateSQL = "UPDATE " + table + " SET " + s.assignments(d.Update) + " WHERE " + s.conditions(d.PK, len(d.Update)+1)
+			}
+		}
+		d.InsertSQL, d.Returning = s.insert(table, d.Insert, d.Identity)
+	}
+
+	for pkg, ok := range imports {
+		switch {
+		case !ok:
+		case strings.Contains(pkg, "."):
+			d.Imports = append(d.Imports, pkg)
+		default:
+			d.Std = append(d.Std, pkg)
+		}
+	}
+	slices.Sort(d.Std)
+	slices.Sort(d.Imports)
+
+	return d
+}
+
+func containsField(fields []field, f field) bool {
+	return slices.ContainsFunc(fields, func(o field) bool { return o.Column == f.Column })
+}
+
+// paramName devuelve el nombre del parámetro del campo, evitando palabras reservadas y repetidos
+func paramName(name string, used map[string]bool) string {
+	r := []rune(name)
+	// Las siglas iniciales se escriben completas en minúsculas (ID -> id, URLFoto -> urlFoto)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		i++
+	}
+	if i > 1 && i < len(r) {
+		i--
+	}
+	p := strings.ToLower(string(r[:max(i, 1)])) + string(r[max(i, 1):])
+
+	for token.IsKeyword(p) || used[p] {
+		p += "_"
+	}
+	used[p] = true
+	return p
+}
+
+// sqlLiteral escribe la consulta como cadena literal de Go, sin escapes cuando es posible
+func sqlLiteral(query string) string {
+	if strings.Contains(query, "`") {
+		return strconv.Quote(query)
+	}
+	return "`" + query + "`"
+}
+
+// sqlBuilder arma las sentencias con el quoting y los marcadores del dialecto
+type sqlBuilder struct {
+	dialect string
+}
+
+func (s sqlBuilder) quote(ident string) string {
+	switch s.dialect {
+	case config.DatabaseTypeMssql:
+		return "[" + strings.ReplaceAll(ident, "]", "]]") + "]"
+	case config.DatabaseTypeMysql:
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	default:
+		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	}
+}
+
+func (s sqlBuilder) table(t database.Table) string {
+	if t.Schema == "" || s.dialect == config.DatabaseTypeSqlite {
+		return s.quote(t.Name)
+	}
+	return s.quote(t.Schema) + "." + s.quote(t.Name)
+}
+
+func (s sqlBuilder) columns(fields []field, prefix string) string {
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		cols[i] = prefix + s.quote(f.Column)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func (s sqlBuilder) conditions(fields []field, first int) string {
+	conds := make([]string, len(fields))
+	for i, f := range fields {
+		conds[i] = s.quote(f.Column) + " = " + database.Placeholder(s.dialect, first+i)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (s sqlBuilder) assignments(fields []field) string {
+	sets := make([]string, len(fields))
+	for i, f := range fields {
+		sets[i] = s.quote(f.Column) + " = " + database.Placeholder(s.dialect, i+1)
+	}
+	return strings.Join(sets, ", ")
+}
+
+func (s sqlBuilder) list(base string, order []field) (string, string) {
+	orderBy := " ORDER BY " + s.columns(order, "")
+	if s.dialect == config.DatabaseTypeMssql {
+		return base + orderBy + " OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY", "offset, limit"
+	}
+	return base + orderBy + " LIMIT " + database.Placeholder(s.dialect, 1) + " OFFSET " + database.Placeholder(s.dialect, 2), "limit, offset"
+}
+
+// insert arma el INSERT; en PostgreSQL, SQLite y SQL Server devuelve las columnas IDENTITY
+func (s sqlBuilder) insert(table string, fields, identity []field) (string, bool) {
+	returning := len(identity) > 0 && s.dialect != config.DatabaseTypeMysql
+
+	values := "DEFAULT VALUES"
+	if s.dialect == config.DatabaseTypeMysql {
+		values = "() VALUES ()"
+	}
+	cols := ""
+	if len(fields) > 0 {
+		marks := make([]string, len(fields))
+		for i := range fields {
+			marks[i] = database.Placeholder(s.dialect, i+1)
+		}
+		cols = " (" + s.columns(fields, "") + ")"
+		values = "VALUES (" + strings.Join(marks, ", ") + ")"
+	}
+
+	switch {
+	case !returning:
+		return "INSERT INTO " + table + cols + " " + values, false
+	case s.dialect == config.DatabaseTypeMssql:
+		return "INSERT INTO " + table + cols + " OUTPUT " + s.columns(identity, "INSERTED.") + " " + values, true
+	default:
+		return "INSERT INTO " + table + cols + " " + values + " RETURNING " + s.columns(identity, ""), true
+	}
+}
@@ -0,0 +1,138 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+var clientes = database.Table{
+	TableRef: database.TableRef{Schema: "dbo", Name: "clientes", Kind: database.KindTable},
+	Columns: []database.Column{
+		{Name: "id", DatabaseType: "INT", Position: 1, Identity: true},
+		{Name: "nombre", DatabaseType: "NVARCHAR", MaxLength: 100, Position: 2},
+		{Name: "email", DatabaseType: "NVARCHAR", Nullable: true, Position: 3},
+		{Name: "creado_en", DatabaseType: "DATETIME2", Position: 4},
+		{Name: "guid", DatabaseType: "UNIQUEIDENTIFIER", Position: 5},
+	},
+	PrimaryKey: []string{"id"},
+}
+
+// TestGenerateTable valida el código generado para una tabla de SQL Server
+func TestGenerateTable(t *testing.T) {
+	src, err := GenerateTable(clientes, Options{Package: "models", Dialect: "mssql"})
+	if err != nil {
+		t.Fatalf("No se esperaba error al generar, pero obtuvo: %v", err)
+	}
+	code := string(src)
+
+	for _, want := range []string{
+		"package models",
+		`"time"`,
+		`mssql "github.com/microsoft/go-mssqldb"`,
+		"type Clientes struct {",
+		"ID int32 `db:\"id\" json:\"id\"`",
+		"Email *string `db:\"email\" json:\"email\"`",
+		"CreadoEn time.Time",
+		"GUID mssql.UniqueIdentifier",
+		"SELECT [id], [nombre], [email], [creado_en], [guid] FROM [dbo].[clientes] ORDER BY [id] OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY",
+		"INSERT INTO [dbo].[clientes] ([nombre], [email], [creado_en], [guid]) OUTPUT INSERTED.[id] VALUES (@p1, @p2, @p3, @p4)",
+		"UPDATE [dbo].[clientes] SET [nombre] = @p1, [email] = @p2, [creado_en] = @p3, [guid] = @p4 WHERE [id] = @p5",
+		"func (r *ClientesRepository) Get(ctx context.Context, id int32) (*Clientes, error)",
+		"Query(ctx, sqlClientesList, offset, limit)",
+		".Scan(&v.ID)",
+		"return database.FromOr(ctx, r.db)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Se esperaba que el código generado contenga %q:\n%s", want, code)
+		}
+	}
+}
+
+// TestGenerateDialects valida las consultas y marcadores de cada dialecto
+func TestGenerateDialects(t *testing.T) {
+	cases := map[string][]string{
+		"postgres": {
+			`FROM "dbo"."clientes" ORDER BY "id" LIMIT $1 OFFSET $2`,
+			`VALUES ($1, $2, $3, $4) RETURNING "id"`,
+			"Query(ctx, sqlClientesList, limit, offset)",
+		},
+		"mysql": {
+			"\"SELECT `id`, `nombre`",
+			"VALUES (?, ?, ?, ?)\"",
+			"_, err := r.querier(ctx).Exec(ctx, sqlClientesInsert",
+		},
+	}
+
+	for dialect, wants := range cases {
+		t.Run(dialect, func(t *testing.T) {
+			src, err := GenerateTable(clientes, Options{Package: "models", Dialect: dialect})
+			if err != nil {
+				t.Fatalf("No se esperaba error al generar, pero obtuvo: %v", err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("Se esperaba que el código generado contenga %q:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateView valida que una vista solo genere el método List
+func TestGenerateView(t *testing.T) {
+	view := database.Table{
+		TableRef: database.TableRef{Schema: "public", Name: "v_ventas", Kind: database.KindView},
+		Columns:  []database.Column{{Name: "total", DatabaseType: "NUMERIC", Nullable: true}},
+	}
+
+	files, err := Generate([]database.Table{view}, Options{Package: "models", Dialect: "postgres"})
+	if err != nil {
+		t.Fatalf("No se esperaba error al generar, pero obtuvo: %v", err)
+	}
+	code := string(files[0].Content)
+	if files[0].Name != "v_ventas.go" {
+		t.Errorf("Se esperaba el archivo v_ventas.go, obtuvo: %s", files[0].Name)
+	}
+	if !strings.Contains(code, "Total *float64") || !strings.Contains(code, "func (r *VVentasRepository) List(") {
+		t.Errorf("Se esperaba el campo Total y el método List en la vista, obtuvo:\n%s", code)
+	}
+	for _, method := range []string{") Get(", ") Insert(", ") Update(", ") Delete(", "mistake"} {
+		if strings.Contains(code, method) {
+			t.Errorf("No se esperaba que una vista genere %s", method)
+		}
+	}
+}
+
+// TestGenerateOptions valida los errores de las opciones
+func TestGenerateOptions(t *testing.T) {
+	if _, err := Generate(nil, Options{Package: "mis-modelos", Dialect: "postgres"}); err == nil {
+		t.Error("Se esperaba un error con un nombre de paquete inválido")
+	}
+	if _, err := Generate(nil, Options{Package: "models", Dialect: "oracle"}); err == nil {
+		t.Error("Se esperaba un error con un dialecto inválido")
+	}
+}
+
+// TestNames valida la conversión de nombres de columnas a identificadores de Go
+func TestNames(t *testing.T) {
+	for in, want := range map[string]string{
+		"cliente_id":   "ClienteID",
+		"url_foto":     "URLFoto",
+		"FechaAlta":    "FechaAlta",
+		"2fa":          "X2fa",
+		"tipo-persona": "TipoPersona",
+	} {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q): se esperaba %q, obtuvo: %q", in, want, got)
+		}
+	}
+
+	used := map[string]bool{"ctx": true}
+	for in, want := range map[string]string{"ID": "id", "URLFoto": "urlFoto", "Type": "type_", "Ctx": "ctx_"} {
+		if got := paramName(in, used); got != want {
+			t.Errorf("paramName(%q): se esperaba %q, obtuvo: %q", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// goType es el tipo Go de una columna y la importación que requiere
+type goType struct {
+	name     string
+	pkg      string
+	nillable bool
+}
+
+var (
+	tInt16   = goType{name: "int16"}
+	tInt32   = goType{name: "int32"}
+	tInt64   = goType{name: "int64"}
+	tFloat32 = goType{name: "float32"}
+	tFloat64 = goType{name: "float64"}
+	tBool    = goType{name: "bool"}
+	tString  = goType{name: "string"}
+	tTime    = goType{name: "time.Time", pkg: `"time"`}
+	tBytes   = goType{name: "[]byte", nillable: true}
+	tJSON    = goType{name: "json.RawMessage", pkg: `"encoding/json"`, nillable: true}
+	tAny     = goType{name: "any", nillable: true}
+	tGUID    = goType{name: "mssql.UniqueIdentifier", pkg: `mssql "github.com/microsoft/go-mssqldb"`}
+)
+
+// baseTypes asocia los tipos comunes a todos los motores. Los decimales se representan con float64;
+// si se requiere precisión exacta se debe cambiar el tipo en el código generado
+var baseTypes = map[string]goType{
+	"SMALLINT": tInt16, "INT2": tInt16, "TINYINT": tInt16,
+	"INT": tInt32, "INT4": tInt32, "INTEGER": tInt32, "MEDIUMINT": tInt32, "SERIAL": tInt32,
+	"BIGINT": tInt64, "INT8": tInt64, "BIGSERIAL": tInt64,
+	"FLOAT4": tFloat32, "REAL": tFloat32,
+	"FLOAT": tFloat64, "FLOAT8": tFloat64, "DOUBLE": tFloat64, "DOUBLE PRECISION": tFloat64,
+	"NUMERIC": tFloat64, "DECIMAL": tFloat64, "MONEY": tFloat64, "SMALLMONEY": tFloat64,
+	"BOOL": tBool, "BOOLEAN": tBool, "BIT": tBool,
+	"TEXT": tString, "VARCHAR": tString, "CHAR": tString, "BPCHAR": tString, "NAME": tString,
+	"CITEXT": tString, "UUID": tString, "NVARCHAR": tString, "NCHAR": tString, "NTEXT": tString,
+	"XML": tString, "TINYTEXT": tString, "MEDIUMTEXT": tString, "LONGTEXT": tString, "ENUM": tString,
+	"DATE": tTime, "TIMESTAMP": tTime, "TIMESTAMPTZ": tTime, "DATETIME": tTime, "DATETIME2": tTime,
+	"SMALLDATETIME": tTime, "DATETIMEOFFSET": tTime,
+	"BYTEA": tBytes, "BINARY": tBytes, "VARBINARY": tBytes, "IMAGE": tBytes, "BLOB": tBytes,
+	"TINYBLOB": tBytes, "MEDIUMBLOB": tBytes, "LONGBLOB": tBytes,
+	"JSON": tJSON, "JSONB": tJSON,
+}
+
+// dialectTypes corrige los tipos cuyo significado cambia según el motor
+var dialectTypes = map[string]map[string]goType{
+	config.DatabaseTypeMssql: {
+		"TIME":             tTime,
+		"UNIQUEIDENTIFIER": tGUID,
+		"TINYINT":          goType{name: "uint8"},
+	},
+	config.DatabaseTypeMysql: {
+		"TIME": tString,
+	},
+	// SQLite usa afinidad de tipos: INTEGER y REAL siempre son de 64 bits
+	config.DatabaseTypeSqlite: {
+		"INTEGER": tInt64,
+		"INT":     tInt64,
+		"REAL":    tFloat64,
+		"":        tAny,
+	},
+}
+
+// columnType devuelve el tipo Go de la columna; las columnas anulables usan punteros
+func columnType(dialect string, c database.Column) goType {
+	dbType := c.DatabaseType
+	if i := strings.IndexByte(dbType, '('); i >= 0 {
+		dbType = strings.TrimSpace(dbType[:i])
+	}
+	dbType = strings.TrimSuffix(dbType, " UNSIGNED")
+
+	t, ok := dialectTypes[dialect][dbType]
+	if !ok {
+		if t, ok = baseTypes[dbType]; !ok {
+			t = tAny
+		}
+	}
+
+	if c.Nullable && !t.nillable {
+		t.name = "*" + t.name
+	}
+	return t
+}
+
+// initialisms son las siglas que se escriben en mayúsculas en los nombres Go
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "UUID": true, "API": true, "HTTP": true, "JSON": true, "SQL": true,
+	"IP": true, "GUID": true, "RUC": true, "RFC": true, "XML": true,
+}
+
+// goName convierte un identificador de la base de datos (snake_case, con espacios o guiones) en un nombre
+// Go exportado
+func goName(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.' || r == '$'
+	})
+
+	var b strings.Builder
+	for _, p := range parts {
+		if up := strings.ToUpper(p); initialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// fileName devuelve el nombre de archivo del código generado para la tabla
+func fileName(t database.Table) string {
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "$", "_").Replace(t.Name)) + ".go"
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"errors"
+)
+
+// txKey es la llave de la transacción activa en el contexto
+type txKey struct{}
+
+// dbKey es la llave de la conexión asignada a la petición en el contexto (ej. la base de datos del tenant)
+type dbKey struct{}
+
+// WithTx devuelve un contexto que transporta la transacción `tx`, para que los repositorios invocados
+// con él se unan a ella a través de From
+func WithTx(ctx context.Context, tx Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFrom devuelve la transacción activa en el contexto, si existe
+func TxFrom(ctx context.Context) (Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(Tx)
+	return tx, ok
+}
+
+// WithDatabase devuelve un contexto que transporta la conexión `db`, que From y RunInTx usan en lugar de
+// la conexión global. Lo usa el middleware de tenants para dirigir cada petición a la base de su cliente
+func WithDatabase(ctx context.Context, db Database) context.Context {
+	return context.WithValue(ctx, dbKey{}, db)
+}
+
+// DatabaseFrom devuelve la conexión asignada en el contexto, si existe
+func DatabaseFrom(ctx context.Context) (Database, bool) {
+	db, ok := ctx.Value(dbKey{}).(Database)
+	return db, ok
+}
+
+// databaseFor devuelve la conexión asignada en el contexto o, si no hay una, la conexión global
+func databaseFor(ctx context.Context) Database {
+	if db, ok := DatabaseFrom(ctx); ok {
+		return db
+	}
+	return GetDatabase()
+}
+
+// From devuelve la transacción activa en el contexto o, si no hay una, la conexión del contexto (ver
+// WithDatabase) o la conexión global. Los repositorios deben usarlo en lugar de GetDatabase para poder
+// participar de la transacción de quien los invoca:
+//
+//	func (r *Clientes) Guardar(ctx context.Context, c Cliente) error {
+//		_, err := database.From(ctx).Exec(ctx, "UPDATE clientes SET nombre = $1 WHERE id = $2", c.Nombre, c.ID)
+//		return err
+//	}
+func From(ctx context.Context) Querier {
+	return FromOr(ctx, nil)
+}
+
+// FromOr es como From, pero usa `db` en lugar de la conexión global cuando el contexto no tiene una
+// transacción ni una conexión. Lo usan los repositorios generados (ver codegen), que reciben su conexión
+// al crearse; con `db` nil equivale a From
+func FromOr(ctx context.Context, db Database) Querier {
+	if tx, ok := TxFrom(ctx); ok {
+		return tx
+	}
+	if db == nil {
+		return databaseFor(ctx)
+	}
+	if cdb, ok := DatabaseFrom(ctx); ok {
+		return cdb
+	}
+	return db
+}
+
+// RunInTx ejecuta `fn` dentro de una transacción. Si el contexto ya transporta una, `fn` se une a ella y
+// la confirmación queda a cargo de quien la inició; en caso contrario se inicia una nueva sobre la
+// conexión del contexto o la global, que se confirma si `fn` no devuelve error y se revierte en caso
+// contrario (o ante un panic)
+func RunInTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := TxFrom(ctx); ok {
+		return fn(ctx)
+	}
+
+	db := databaseFor(ctx)
+	if db == nil {
+		return errors.New("no hay una conexión de base de datos inicializada")
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = fn(WithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
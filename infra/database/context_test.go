@@ -0,0 +1,67 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/databasetest"
+)
+
+// TestRunInTx_Nested valida que una transacción anidada se una a la del contexto
+func TestRunInTx_Nested(t *testing.T) {
+	db := databasetest.New(t).Install()
+	db.ExpectBegin()
+	db.ExpectExec("UPDATE a SET x = 1")
+	db.ExpectExec("UPDATE b SET y = 2")
+	db.ExpectCommit()
+
+	err := database.RunInTx(context.Background(), func(ctx context.Context) error {
+		if _, err := database.From(ctx).Exec(ctx, "UPDATE a SET x = 1"); err != nil {
+			return err
+		}
+		return database.RunInTx(ctx, func(ctx context.Context) error {
+			_, err := database.From(ctx).Exec(ctx, "UPDATE b SET y = 2")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+
+	for _, st := range db.Statements() {
+		if !st.InTx {
+			t.Errorf("Se esperaba que %q se ejecute dentro de la transacción", st.SQL)
+		}
+	}
+}
+
+// TestRunInTx_Rollback valida la reversión cuando la función devuelve un error
+func TestRunInTx_Rollback(t *testing.T) {
+	db := databasetest.New(t).Install()
+	db.ExpectBegin()
+	db.ExpectRollback()
+
+	boom := errors.New("boom")
+	if err := database.RunInTx(context.Background(), func(context.Context) error { return boom }); !errors.Is(err, boom) {
+		t.Errorf("Se esperaba el error de la función, obtuvo: %v", err)
+	}
+
+	if q := database.From(context.Background()); q != database.Querier(db) {
+		t.Error("Sin transacción en el contexto se esperaba la conexión global")
+	}
+}
+
+// TestFromOr valida que la conexión del contexto tenga prioridad sobre la del repositorio
+func TestFromOr(t *testing.T) {
+	repo, tenant := databasetest.New(t), databasetest.New(t)
+	ctx := context.Background()
+
+	if got := database.FromOr(ctx, repo); got != database.Querier(repo) {
+		t.Errorf("Se esperaba la conexión del repositorio, obtuvo: %v", got)
+	}
+	if got := database.FromOr(database.WithDatabase(ctx, tenant), repo); got != database.Querier(tenant) {
+		t.Errorf("Se esperaba la conexión del contexto, obtuvo: %v", got)
+	}
+}
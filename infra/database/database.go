@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"reflect"
 
 	"github.com/wfrscltech/vulcano/config"
 )
@@ -19,15 +21,41 @@ type Rows interface {
 	Next() bool
 	Scan(dest ...any) error
 	Close()
+
+	// Err devuelve el error que interrumpió la iteración; se debe consultar cuando Next devuelve false para
+	// distinguir el fin de los registros de una falla a mitad del conjunto
+	Err() error
+	// Columns devuelve los nombres de las columnas del resultado
+	Columns() ([]string, error)
+	// ColumnTypes devuelve la descripción de las columnas del resultado
+	ColumnTypes() ([]ColumnType, error)
 }
 
-// DB define operaciones básicas que puede usar la capa de negocio
-type Database interface {
-	Close()
+// ColumnType describe una columna de un conjunto de registros
+type ColumnType struct {
+	// Nombre de la columna
+	Name string
+	// Nombre del tipo en la base de datos, en mayúsculas (ej. INT8, VARCHAR, NVARCHAR, DATETIME2)
+	DatabaseType string
+	// Tipo Go adecuado para escanear la columna; `any` si el driver no lo informa
+	ScanType reflect.Type
+}
+
+// Querier define las operaciones de consulta comunes a Database y Tx
+type Querier interface {
 	Query(ctx context.Context, query string, args ...any) (Rows, error)
 	QueryRow(ctx context.Context, query string, args ...any) Row
 	Exec(ctx context.Context, query string, args ...any) (int64, error)
 
+	// Carga masiva de filas leídas desde `src`; devuelve la cantidad de filas escritas
+	BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error)
+}
+
+// DB define operaciones básicas que puede usar la capa de negocio
+type Database interface {
+	Querier
+	Close()
+
 	// Transacciones
 	BeginTx(ctx context.Context) (Tx, error)
 
@@ -37,9 +65,7 @@ type Database interface {
 
 // Tx representa una transacción
 type Tx interface {
-	Query(ctx context.Context, query string, args ...any) (Rows, error)
-	QueryRow(ctx context.Context, query string, args ...any) Row
-	Exec(ctx context.Context, query string, args ...any) (int64, error)
+	Querier
 	Commit(ctx context.Context) error
 	Rollback(ctx context.Context) error
 }
@@ -48,16 +74,48 @@ func GetDatabase() Database {
 	return cnx
 }
 
+// SetDatabase reemplaza la conexión global devuelta por GetDatabase. Pensado para inyectar dobles de
+// prueba (ver el paquete databasetest) o conexiones creadas fuera de New
+func SetDatabase(db Database) {
+	cnx = db
+}
+
+// New abre la conexión global según la configuración. Con `Lazy` devuelve de inmediato y la conexión se
+// establece en segundo plano; mientras tanto las operaciones fallan con un error `mistake.Unavailable`
 func New(dcfg config.DatabaseConfig) error {
-	var err error = nil
-	switch dcfg.Typo {
-	case "postgres":
-		cnx, err = newPostgresCnx(dcfg)
-		return err
-	case "mssql":
-		cnx, err = newMSSQLCnx(dcfg)
-		return err
-	default:
-		return fmt.Errorf("no se reconoce el tipo de base de datos %s", dcfg.Typo)
+	var err error
+	cnx, err = Open(dcfg)
+	return err
+}
+
+// Open abre una conexión según la configuración, igual que New, pero sin reemplazar la conexión global.
+// Sirve para mantener varias conexiones, como las de cada tenant
+func Open(dcfg config.DatabaseConfig) (Database, error) {
+	open, ok := openers[dcfg.Typo]
+	if !ok {
+		return nil, fmt.Errorf("no se reconoce el tipo de base de datos %s", dcfg.Typo)
 	}
+
+	var db Database
+	if dcfg.Lazy {
+		db = newLazy(func() (Database, error) { return open(dcfg) })
+	} else {
+		var err error
+		if db, err = open(dcfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if dcfg.LogQueries {
+		db = WithQueryHook(db, LogQueries(slog.LevelDebug))
+	}
+	return db, nil
+}
+
+// openers son los constructores de la conexión de cada tipo de base de datos
+var openers = map[string]func(config.DatabaseConfig) (Database, error){
+	config.DatabaseTypePostgres: newPostgresCnx,
+	config.DatabaseTypeMssql:    newMSSQLCnx,
+	config.DatabaseTypeSqlite:   newSQLiteCnx,
+	config.DatabaseTypeMysql:    newMySQLCnx,
 }
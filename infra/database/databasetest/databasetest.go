@@ -0,0 +1,398 @@
+// Package databasetest provee un doble de prueba programable de database.Database para probar
+// repositorios sin un servidor de base de datos.
+//
+//	db := databasetest.New(t)
+//	db.Install()
+//	db.ExpectQuery("SELECT id, nombre FROM clientes WHERE id = $1").
+//		WithArgs(7).
+//		WillReturnRows(databasetest.NewRows("id", "nombre").AddRow(7, "Ana"))
+//
+// Las expectativas se consumen en orden; al terminar el test se reportan las que no se cumplieron y las
+// transacciones que quedaron abiertas.
+package databasetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Statement registra una sentencia ejecutada contra el doble de prueba
+type Statement struct {
+	// Tipo de operación: Query, Exec o BulkInsert
+	Kind string
+	// Texto de la consulta tal como se recibió, o la tabla destino en BulkInsert
+	SQL string
+	// Argumentos recibidos
+	Args []any
+	// Columnas y filas recibidas en BulkInsert
+	Columns []string
+	Rows    [][]any
+	// Indica si la sentencia se ejecutó dentro de una transacción
+	InTx bool
+}
+
+// Fake implementa database.Database con respuestas programadas
+type Fake struct {
+	t testing.TB
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	statements   []Statement
+	failures     []string
+	txs          []*fakeTx
+	closed       bool
+}
+
+// New crea un doble de prueba cuyas expectativas se verifican automáticamente al terminar el test
+func New(t testing.TB) *Fake {
+	f := &Fake{t: t}
+	t.Cleanup(func() {
+		if err := f.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return f
+}
+
+// Install registra el doble como la conexión global de database.GetDatabase y restaura la anterior al
+// terminar el test
+func (f *Fake) Install() *Fake {
+	prev := database.GetDatabase()
+	database.SetDatabase(f)
+	f.t.Cleanup(func() { database.SetDatabase(prev) })
+	return f
+}
+
+// ExpectQuery espera una consulta (Query o QueryRow) con el texto exacto, sin considerar espacios en blanco
+func (f *Fake) ExpectQuery(query string) *Expectation {
+	return f.expect(&Expectation{kind: kindQuery, sql: query})
+}
+
+// ExpectQueryRegexp espera una consulta (Query o QueryRow) que coincida con la expresión regular
+func (f *Fake) ExpectQueryRegexp(pattern string) *Expectation {
+	return f.expect(&Expectation{kind: kindQuery, re: regexp.MustCompile(pattern)})
+}
+
+// ExpectExec espera una sentencia Exec con el texto exacto, sin considerar espacios en blanco
+func (f *Fake) ExpectExec(query string) *Expectation {
+	return f.expect(&Expectation{kind: kindExec, sql: query})
+}
+
+// ExpectExecRegexp espera una sentencia Exec que coincida con la expresión regular
+func (f *Fake) ExpectExecRegexp(pattern string) *Expectation {
+	return f.expect(&Expectation{kind: kindExec, re: regexp.MustCompile(pattern)})
+}
+
+// ExpectBulkInsert espera una carga masiva sobre la tabla indicada. Las filas del RowSource se consumen y
+// se registran en Statements; el resultado es la cantidad de filas leídas salvo que se programe un error
+func (f *Fake) ExpectBulkInsert(table string) *Expectation {
+	return f.expect(&Expectation{kind: kindBulk, sql: table})
+}
+
+// ExpectBegin espera el inicio de una transacción
+func (f *Fake) ExpectBegin() *Expectation {
+	return f.expect(&Expectation{kind: kindBegin})
+}
+
+// ExpectCommit espera la confirmación de una transacción
+func (f *Fake) ExpectCommit() *Expectation {
+	return f.expect(&Expectation{kind: kindCommit})
+}
+
+// ExpectRollback espera la reversión de una transacción
+func (f *Fake) ExpectRollback() *Expectation {
+	return f.expect(&Expectation{kind: kindRollback})
+}
+
+// Statements devuelve las sentencias ejecutadas, en orden
+func (f *Fake) Statements() []Statement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Statement(nil), f.statements...)
+}
+
+// ExpectationsWereMet devuelve un error si quedaron expectativas sin cumplir, operaciones inesperadas o
+// transacciones sin Commit ni Rollback
+func (f *Fake) ExpectationsWereMet() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	problems := append([]string(nil), f.failures...)
+	for _, e := range f.expectations {
+		if !e.fulfilled {
+			problems = append(problems, fmt.Sprintf("expectativa no cumplida: %s", e))
+		}
+	}
+	for i, tx := range f.txs {
+		if !tx.done {
+			problems = append(problems, fmt.Sprintf("la transacción #%d terminó sin Commit ni Rollback", i+1))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("databasetest:\n  " + strings.Join(problems, "\n  "))
+}
+
+// --- Implementación de database.Database ---
+
+func (f *Fake) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+func (f *Fake) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	return f.query(ctx, false, query, args)
+}
+
+func (f *Fake) QueryRow(ctx context.Context, query string, args ...any) database.Row {
+	return f.queryRow(ctx, false, query, args)
+}
+
+func (f *Fake) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	return f.exec(ctx, false, query, args)
+}
+
+func (f *Fake) BulkInsert(ctx context.Context, table string, columns []string, src database.RowSource, opts ...database.BulkOption) (int64, error) {
+	return f.bulkInsert(ctx, false, table, columns, src)
+}
+
+func (f *Fake) BeginTx(ctx context.Context) (database.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e, err := f.next(kindBegin, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	tx := &fakeTx{fake: f}
+	f.mu.Lock()
+	f.txs = append(f.txs, tx)
+	f.mu.Unlock()
+
+	return tx, nil
+}
+
+func (f *Fake) RawConnection() any {
+	return nil
+}
+
+// --- Resolución de expectativas ---
+
+func (f *Fake) expect(e *Expectation) *Expectation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.expectations = append(f.expectations, e)
+	return e
+}
+
+// next consume la siguiente expectativa pendiente, que debe coincidir con la operación recibida
+func (f *Fake) next(kind, query string, args []any) (*Expectation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, f.fail("%s %q invocado después de Close", kind, query)
+	}
+
+	for _, e := range f.expectations {
+		if e.fulfilled {
+			continue
+		}
+		if err := e.match(kind, query, args); err != nil {
+			return nil, f.fail("%s", err)
+		}
+		e.fulfilled = true
+		return e, nil
+	}
+
+	if query == "" {
+		return nil, f.fail("%s inesperado: no hay expectativas pendientes", kind)
+	}
+	return nil, f.fail("%s inesperado: no hay expectativas pendientes para %q", kind, query)
+}
+
+// fail registra un fallo para reportarlo al terminar el test y lo devuelve como error
+func (f *Fake) fail(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	f.failures = append(f.failures, msg)
+	return errors.New("databasetest: " + msg)
+}
+
+func (f *Fake) record(kind string, inTx bool, query string, args []any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.statements = append(f.statements, Statement{Kind: kind, SQL: query, Args: args, InTx: inTx})
+}
+
+func (f *Fake) query(ctx context.Context, inTx bool, query string, args []any) (database.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.record(kindQuery, inTx, query, args)
+	e, err := f.next(kindQuery, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return newFakeRows(e.rows), nil
+}
+
+func (f *Fake) queryRow(ctx context.Context, inTx bool, query string, args []any) database.Row {
+	rows, err := f.query(ctx, inTx, query, args)
+	if err != nil {
+		return &fakeRow{err: err}
+	}
+
+	r := rows.(*fakeRows)
+	if len(r.rows.values) == 0 {
+		return &fakeRow{}
+	}
+	return &fakeRow{values: r.rows.values[0]}
+}
+
+func (f *Fake) exec(ctx context.Context, inTx bool, query string, args []any) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	f.record(kindExec, inTx, query, args)
+	e, err := f.next(kindExec, query, args)
+	if err != nil {
+		return 0, err
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	return e.affected, nil
+}
+
+func (f *Fake) bulkInsert(ctx context.Context, inTx bool, table string, columns []string, src database.RowSource) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var rows [][]any
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, append([]any(nil), values...))
+	}
+	if err := src.Err(); err != nil {
+		return 0, err
+	}
+
+	f.mu.Lock()
+	f.statements = append(f.statements, Statement{Kind: kindBulk, SQL: table, Columns: columns, Rows: rows, InTx: inTx})
+	f.mu.Unlock()
+
+	e, err := f.next(kindBulk, table, nil)
+	if err != nil {
+		return 0, err
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	return int64(len(rows)), nil
+}
+
+// fakeTx implementa database.Tx sobre el doble de prueba
+type fakeTx struct {
+	fake *Fake
+	done bool
+}
+
+func (tx *fakeTx) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	if err := tx.active(kindQuery, query); err != nil {
+		return nil, err
+	}
+	return tx.fake.query(ctx, true, query, args)
+}
+
+func (tx *fakeTx) QueryRow(ctx context.Context, query string, args ...any) database.Row {
+	if err := tx.active(kindQuery, query); err != nil {
+		return &fakeRow{err: err}
+	}
+	return tx.fake.queryRow(ctx, true, query, args)
+}
+
+func (tx *fakeTx) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	if err := tx.active(kindExec, query); err != nil {
+		return 0, err
+	}
+	return tx.fake.exec(ctx, true, query, args)
+}
+
+func (tx *fakeTx) BulkInsert(ctx context.Context, table string, columns []string, src database.RowSource, opts ...database.BulkOption) (int64, error) {
+	if err := tx.active(kindBulk, table); err != nil {
+		return 0, err
+	}
+	return tx.fake.bulkInsert(ctx, true, table, columns, src)
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	return tx.finish(kindCommit)
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	return tx.finish(kindRollback)
+}
+
+// active valida que la transacción no haya terminado
+func (tx *fakeTx) active(kind, query string) error {
+	tx.fake.mu.Lock()
+	defer tx.fake.mu.Unlock()
+
+	if tx.done {
+		return tx.fake.fail("%s %q invocado sobre una transacción finalizada", kind, query)
+	}
+	return nil
+}
+
+func (tx *fakeTx) finish(kind string) error {
+	tx.fake.mu.Lock()
+	done := tx.done
+	tx.done = true
+	tx.fake.mu.Unlock()
+
+	// Un Rollback posterior a Commit se ignora para permitir el patrón `defer tx.Rollback(ctx)`
+	if done {
+		if kind == kindRollback {
+			return nil
+		}
+		tx.fake.mu.Lock()
+		defer tx.fake.mu.Unlock()
+		return tx.fake.fail("%s invocado sobre una transacción finalizada", kind)
+	}
+
+	e, err := tx.fake.next(kind, "", nil)
+	if err != nil {
+		return err
+	}
+	return e.err
+}
@@ -0,0 +1,127 @@
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// recorder captura los errores reportados por el doble sin fallar el test real
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Error(args ...any) {
+	for _, a := range args {
+		r.errors = append(r.errors, a.(error).Error())
+	}
+}
+
+func (r *recorder) Cleanup(func()) {}
+
+// TestFake_QueryAndScan valida la coincidencia exacta, los argumentos y el escaneo de filas
+func TestFake_QueryAndScan(t *testing.T) {
+	db := New(t).Install()
+	db.ExpectQuery("SELECT id, nombre, saldo FROM clientes WHERE activo = $1").
+		WithArgs(true).
+		WillReturnRows(NewRows("id", "nombre", "saldo").AddRow(int64(1), "Ana", 10.5).AddRow(int64(2), "Luis", nil))
+
+	rows, err := database.GetDatabase().Query(context.Background(),
+		"SELECT id, nombre, saldo\n  FROM clientes\n WHERE activo = $1", true)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var id int
+		var name string
+		var balance *float64
+		if err := rows.Scan(&id, &name, &balance); err != nil {
+			t.Fatalf("Error al escanear: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	if strings.Join(names, ",") != "Ana,Luis" {
+		t.Errorf("Se esperaban Ana,Luis, obtuvo: %v", names)
+	}
+}
+
+// TestFake_ExecAndTransaction valida el registro de sentencias y el flujo de transacciones
+func TestFake_ExecAndTransaction(t *testing.T) {
+	db := New(t)
+	db.ExpectBegin()
+	db.ExpectExecRegexp(`^UPDATE cuentas SET saldo`).WithArgs(100, AnyArg()).WillReturnResult(1)
+	db.ExpectCommit()
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	n, err := tx.Exec(ctx, "UPDATE cuentas SET saldo = saldo - $1 WHERE id = $2", 100, 7)
+	if err != nil || n != 1 {
+		t.Fatalf("Se esperaba 1 fila afectada sin error, obtuvo: %d, %v", n, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("No se esperaba error en Commit, pero obtuvo: %v", err)
+	}
+
+	st := db.Statements()
+	if len(st) != 1 || !st[0].InTx || st[0].Args[1] != 7 {
+		t.Errorf("Sentencias registradas inesperadas: %+v", st)
+	}
+}
+
+// TestFake_Errors valida errores programados y la ausencia de filas en QueryRow
+func TestFake_Errors(t *testing.T) {
+	db := New(t)
+	boom := errors.New("boom")
+	db.ExpectExec("DELETE FROM clientes").WillReturnError(boom)
+	db.ExpectQuery("SELECT nombre FROM clientes WHERE id = $1").WillReturnRows(NewRows("nombre"))
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "DELETE FROM clientes"); !errors.Is(err, boom) {
+		t.Errorf("Se esperaba el error programado, obtuvo: %v", err)
+	}
+
+	var name string
+	if err := db.QueryRow(ctx, "SELECT nombre FROM clientes WHERE id = $1", 9).Scan(&name); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Se esperaba sql.ErrNoRows, obtuvo: %v", err)
+	}
+}
+
+// TestFake_UnmetExpectations valida el reporte de expectativas pendientes y transacciones abiertas
+func TestFake_UnmetExpectations(t *testing.T) {
+	rec := &recorder{TB: t}
+	db := New(rec)
+	db.ExpectBegin()
+	db.ExpectQuery("SELECT 1")
+
+	ctx := context.Background()
+	if _, err := db.BeginTx(ctx); err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if _, err := db.Exec(ctx, "SELECT 2"); err == nil {
+		t.Error("Se esperaba un error por sentencia inesperada")
+	}
+
+	err := db.ExpectationsWereMet()
+	if err == nil {
+		t.Fatal("Se esperaba un error por expectativas no cumplidas")
+	}
+	for _, want := range []string{`Query "SELECT 1"`, "sin Commit ni Rollback", `Exec "SELECT 2"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Se esperaba que el error contenga %q, obtuvo: %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package databasetest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Tipos de operación que puede esperar el doble de prueba
+const (
+	kindQuery    = "Query"
+	kindExec     = "Exec"
+	kindBulk     = "BulkInsert"
+	kindBegin    = "BeginTx"
+	kindCommit   = "Commit"
+	kindRollback = "Rollback"
+)
+
+// Expectation representa una operación esperada y su respuesta programada
+type Expectation struct {
+	kind    string
+	sql     string
+	re      *regexp.Regexp
+	args    []any
+	hasArgs bool
+
+	rows     *Rows
+	affected int64
+	err      error
+
+	fulfilled bool
+}
+
+// WithArgs indica los argumentos exactos con los que se debe invocar la consulta
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.hasArgs = true
+	return e
+}
+
+// WillReturnRows programa las filas que devolverá la consulta
+func (e *Expectation) WillReturnRows(rows *Rows) *Expectation {
+	e.rows = rows
+	return e
+}
+
+// WillReturnResult programa el número de filas afectadas que devolverá Exec
+func (e *Expectation) WillReturnResult(affected int64) *Expectation {
+	e.affected = affected
+	return e
+}
+
+// WillReturnError programa el error que devolverá la operación
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// String describe la expectativa para los mensajes de error
+func (e *Expectation) String() string {
+	switch {
+	case e.re != nil:
+		return fmt.Sprintf("%s que coincida con /%s/", e.kind, e.re.String())
+	case e.sql != "":
+		return fmt.Sprintf("%s %q", e.kind, e.sql)
+	default:
+		return e.kind
+	}
+}
+
+// match valida que la operación recibida corresponda con la esperada
+func (e *Expectation) match(kind, query string, args []any) error {
+	if e.kind != kind {
+		return fmt.Errorf("se esperaba %s, pero se recibió %s %q", e, kind, query)
+	}
+
+	if e.re != nil && !e.re.MatchString(query) {
+		return fmt.Errorf("se esperaba %s, pero se recibió %q", e, query)
+	}
+
+	if e.re == nil && e.sql != "" && normalize(e.sql) != normalize(query) {
+		return fmt.Errorf("se esperaba %s, pero se recibió %q", e, query)
+	}
+
+	if e.hasArgs && !equalArgs(e.args, args) {
+		return fmt.Errorf("%s: se esperaban los argumentos %v, pero se recibieron %v", e, e.args, args)
+	}
+
+	return nil
+}
+
+// normalize colapsa los espacios en blanco para que el formato de la consulta no afecte la comparación
+func normalize(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// equalArgs compara los argumentos esperados con los recibidos
+func equalArgs(expected, actual []any) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for i := range expected {
+		if m, ok := expected[i].(Matcher); ok {
+			if !m.Match(actual[i]) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(expected[i], actual[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Matcher permite comparar un argumento con una regla propia en lugar de igualdad exacta
+type Matcher interface {
+	Match(v any) bool
+}
+
+// MatcherFunc adapta una función a la interfaz Matcher
+type MatcherFunc func(v any) bool
+
+func (f MatcherFunc) Match(v any) bool {
+	return f(v)
+}
+
+// AnyArg acepta cualquier valor en la posición del argumento
+func AnyArg() Matcher {
+	return MatcherFunc(func(any) bool { return true })
+}
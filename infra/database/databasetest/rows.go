@@ -0,0 +1,205 @@
+package databasetest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Rows construye el conjunto de filas que devolverá una consulta esperada
+type Rows struct {
+	columns []string
+	types   []string
+	values  [][]any
+	errAt   int
+	err     error
+}
+
+// NewRows crea un conjunto de filas vacío con las columnas indicadas
+func NewRows(columns ...string) *Rows {
+	return &Rows{columns: columns, errAt: -1}
+}
+
+// WithTypes indica el nombre del tipo en la base de datos de cada columna, informado por ColumnTypes
+func (r *Rows) WithTypes(types ...string) *Rows {
+	if len(types) != len(r.columns) {
+		panic(fmt.Sprintf("databasetest: se esperaban %d tipos, se recibieron %d", len(r.columns), len(types)))
+	}
+	r.types = types
+	return r
+}
+
+// RowError simula una falla a mitad del conjunto: Next devuelve false al llegar a la fila `row` (base 0)
+// y Err devuelve `err`
+func (r *Rows) RowError(row int, err error) *Rows {
+	r.errAt = row
+	r.err = err
+	return r
+}
+
+// AddRow agrega una fila; la cantidad de valores debe coincidir con la cantidad de columnas
+func (r *Rows) AddRow(values ...any) *Rows {
+	if len(values) != len(r.columns) {
+		panic(fmt.Sprintf("databasetest: se esperaban %d valores, se recibieron %d", len(r.columns), len(values)))
+	}
+	r.values = append(r.values, values)
+	return r
+}
+
+// fakeRows implementa database.Rows sobre un conjunto de filas programado
+type fakeRows struct {
+	rows   *Rows
+	pos    int
+	closed bool
+	err    error
+}
+
+func newFakeRows(r *Rows) *fakeRows {
+	if r == nil {
+		r = NewRows()
+	}
+	return &fakeRows{rows: r, pos: -1}
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed {
+		return false
+	}
+	r.pos++
+	if r.pos == r.rows.errAt {
+		r.err = r.rows.err
+		r.closed = true
+		return false
+	}
+	if r.pos >= len(r.rows.values) {
+		r.closed = true
+		return false
+	}
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.closed || r.pos < 0 || r.pos >= len(r.rows.values) {
+		return errors.New("databasetest: Scan invocado sin una fila activa")
+	}
+	return scanValues(r.rows.values[r.pos], dest)
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+func (r *fakeRows) Err() error {
+	return r.err
+}
+
+func (r *fakeRows) Columns() ([]string, error) {
+	return append([]string(nil), r.rows.columns...), nil
+}
+
+// ColumnTypes informa los tipos indicados con WithTypes y deduce el tipo de escaneo de la primera fila
+func (r *fakeRows) ColumnTypes() ([]database.ColumnType, error) {
+	types := make([]database.ColumnType, len(r.rows.columns))
+	for i, name := range r.rows.columns {
+		types[i] = database.ColumnType{Name: name, ScanType: reflect.TypeFor[any]()}
+		if r.rows.types != nil {
+			types[i].DatabaseType = r.rows.types[i]
+		}
+		if len(r.rows.values) > 0 && r.rows.values[0][i] != nil {
+			types[i].ScanType = reflect.TypeOf(r.rows.values[0][i])
+		}
+	}
+	return types, nil
+}
+
+// fakeRow implementa database.Row devolviendo la primera fila programada
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r *fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.values == nil {
+		return sql.ErrNoRows
+	}
+	return scanValues(r.values, dest)
+}
+
+// scanValues copia los valores programados en los destinos siguiendo reglas similares a database/sql
+func scanValues(values []any, dest []any) error {
+	if len(values) != len(dest) {
+		return fmt.Errorf("databasetest: se esperaban %d destinos en Scan, se recibieron %d", len(values), len(dest))
+	}
+
+	for i := range dest {
+		if err := assign(dest[i], values[i]); err != nil {
+			return fmt.Errorf("databasetest: columna %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// assign asigna `src` en el puntero `dest`, convirtiendo entre tipos compatibles
+func assign(dest, src any) error {
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return errors.New("el destino debe ser un puntero no nulo")
+	}
+	dv = dv.Elem()
+
+	if src == nil {
+		switch dv.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			dv.SetZero()
+			return nil
+		}
+		return fmt.Errorf("no se puede asignar NULL a %s", dv.Type())
+	}
+
+	sv := reflect.ValueOf(src)
+
+	// Destinos anulables (*T) reciben un nuevo puntero con el valor
+	if dv.Kind() == reflect.Pointer && sv.Type() != dv.Type() {
+		p := reflect.New(dv.Type().Elem())
+		if err := assign(p.Interface(), src); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	}
+
+	switch {
+	case sv.Type().AssignableTo(dv.Type()):
+		dv.Set(sv)
+	case isNumeric(sv.Kind()) && isNumeric(dv.Kind()), sv.Type().ConvertibleTo(dv.Type()) && sameFamily(sv, dv):
+		dv.Set(sv.Convert(dv.Type()))
+	default:
+		return fmt.Errorf("no se puede asignar %T a %s", src, dv.Type())
+	}
+
+	return nil
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+// sameFamily evita conversiones sorpresivas como int -> string
+func sameFamily(sv, dv reflect.Value) bool {
+	isText := func(v reflect.Value) bool {
+		return v.Kind() == reflect.String ||
+			(v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8)
+	}
+	return isText(sv) == isText(dv)
+}
@@ -0,0 +1,242 @@
+package database
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/wfrscltech/vulcano/fn"
+)
+
+// ErrNoKeyRing indica que se usó una columna cifrada sin configurar el llavero con SetKeyRing
+var ErrNoKeyRing = errors.New("no se configuró el llavero de cifrado de columnas")
+
+// keyring es el llavero usado por EncryptedString y EncryptedBytes
+var keyring atomic.Pointer[KeyRing]
+
+// KeyRing contiene las llaves AES de cifrado de columnas identificadas por nombre. Los valores se cifran
+// siempre con la llave primaria y se descifran con la llave indicada en su encabezado, de modo que para
+// rotar basta con agregar la llave nueva como primaria y conservar las anteriores hasta recifrar los datos
+// (ver NeedsRotation)
+type KeyRing struct {
+	primary string
+	keys    map[string][]byte
+	blind   []byte
+}
+
+// NewKeyRing crea un llavero con las llaves `keys` (de 16, 24 o 32 bytes) que cifra con `primary`
+func NewKeyRing(primary string, keys map[string][]byte) (*KeyRing, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("la llave primaria `%s` no está en el llavero", primary)
+	}
+
+	kr := &KeyRing{primary: primary, keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("el identificador de llave `%s` debe tener entre 1 y 255 caracteres", id)
+		}
+		if !fn.In(len(key), 16, 24, 32) {
+			return nil, fmt.Errorf("la llave `%s` debe tener 16, 24 o 32 bytes", id)
+		}
+		kr.keys[id] = key
+	}
+	return kr, nil
+}
+
+// WithBlindIndexKey devuelve una copia del llavero que calcula los índices ciegos con `key`. Esta llave es
+// independiente de las de cifrado y no se puede rotar sin recalcular todos los índices
+func (kr *KeyRing) WithBlindIndexKey(key []byte) (*KeyRing, error) {
+	if len(key) < 32 {
+		return nil, errors.New("la llave de índice ciego debe tener al menos 32 bytes")
+	}
+	c := *kr
+	c.blind = key
+	return &c, nil
+}
+
+// SetKeyRing define el llavero usado por las columnas cifradas
+func SetKeyRing(kr *KeyRing) {
+	keyring.Store(kr)
+}
+
+func currentKeyRing() (*KeyRing, error) {
+	kr := keyring.Load()
+	if kr == nil {
+		return nil, ErrNoKeyRing
+	}
+	return kr, nil
+}
+
+// Encrypt cifra `plain` con la llave primaria. El resultado lleva como encabezado el identificador de la
+// llave: un byte con su longitud seguido del identificador
+func (kr *KeyRing) Encrypt(plain []byte) ([]byte, error) {
+	ct, err := fn.Encrypt(plain, kr.keys[kr.primary])
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(kr.primary)+len(ct))
+	out = append(out, byte(len(kr.primary)))
+	out = append(out, kr.primary...)
+	return append(out, ct...), nil
+}
+
+// Decrypt descifra un valor generado por Encrypt con la llave indicada en su encabezado
+func (kr *KeyRing) Decrypt(data []byte) ([]byte, error) {
+	id, ct, err := splitKeyID(data)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := kr.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("la llave `%s` del valor cifrado no está en el llavero", id)
+	}
+	return fn.Decrypt(ct, key)
+}
+
+// NeedsRotation indica si el valor cifrado usa una llave distinta de la primaria y debe recifrarse
+func (kr *KeyRing) NeedsRotation(data []byte) bool {
+	id, _, err := splitKeyID(data)
+	return err == nil && id != kr.primary
+}
+
+// BlindIndex calcula el índice ciego de `plain`: un HMAC-SHA256 determinista que se guarda en una columna
+// aparte para buscar por igualdad sin descifrar (ej. `WHERE national_id_idx = $1`)
+func (kr *KeyRing) BlindIndex(plain []byte) ([]byte, error) {
+	if kr.blind == nil {
+		return nil, errors.New("el llavero no tiene llave de índice ciego")
+	}
+	mac := hmac.New(sha256.New, kr.blind)
+	mac.Write(plain)
+	return mac.Sum(nil), nil
+}
+
+func splitKeyID(data []byte) (string, []byte, error) {
+	if len(data) == 0 || len(data) < 1+int(data[0]) {
+		return "", nil, errors.New("el valor cifrado no tiene encabezado de llave")
+	}
+	n := int(data[0])
+	return string(data[1 : 1+n]), data[1+n:], nil
+}
+
+// --- Tipos de columnas cifradas ---
+
+// EncryptedString es un texto que se guarda cifrado (columnas `bytea` o `varbinary(max)`) con el llavero
+// de SetKeyRing. Implementa sql.Scanner, driver.Valuer y las interfaces de bytea de pgx. Para admitir
+// NULL se usa *EncryptedString
+type EncryptedString string
+
+// EncryptedBytes es como EncryptedString para datos binarios
+type EncryptedBytes []byte
+
+// BlindIndex calcula el índice ciego del texto con el llavero de SetKeyRing
+func (s EncryptedString) BlindIndex() ([]byte, error) {
+	kr, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+	return kr.BlindIndex([]byte(s))
+}
+
+func (s EncryptedString) Value() (driver.Value, error) {
+	return encryptValue([]byte(s))
+}
+
+func (s EncryptedString) BytesValue() ([]byte, error) {
+	return encryptValue([]byte(s))
+}
+
+func (s *EncryptedString) Scan(src any) error {
+	plain, err := decryptSource(src)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plain)
+	return nil
+}
+
+func (s *EncryptedString) ScanBytes(v []byte) error {
+	return s.Scan(v)
+}
+
+// LogValue evita que el texto descifrado llegue a los logs
+func (s EncryptedString) LogValue() slog.Value {
+	return slog.StringValue("[cifrado]")
+}
+
+// BlindIndex calcula el índice ciego de los datos con el llavero de SetKeyRing
+func (b EncryptedBytes) BlindIndex() ([]byte, error) {
+	kr, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+	return kr.BlindIndex(b)
+}
+
+func (b EncryptedBytes) Value() (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return encryptValue(b)
+}
+
+func (b EncryptedBytes) BytesValue() ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return encryptValue(b)
+}
+
+func (b *EncryptedBytes) Scan(src any) error {
+	plain, err := decryptSource(src)
+	if err != nil {
+		return err
+	}
+	*b = plain
+	return nil
+}
+
+func (b *EncryptedBytes) ScanBytes(v []byte) error {
+	return b.Scan(v)
+}
+
+// LogValue evita que los datos descifrados lleguen a los logs
+func (b EncryptedBytes) LogValue() slog.Value {
+	return slog.StringValue("[cifrado]")
+}
+
+func encryptValue(plain []byte) ([]byte, error) {
+	kr, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+	return kr.Encrypt(plain)
+}
+
+// decryptSource descifra el valor leído de la base; NULL se lee como vacío
+func decryptSource(src any) ([]byte, error) {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		if v == nil {
+			return nil, nil
+		}
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("no se puede descifrar un valor de tipo %T", src)
+	}
+
+	kr, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+	return kr.Decrypt(data)
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/wfrscltech/vulcano/config"
+)
+
+func keyRing(t *testing.T, primary string) *KeyRing {
+	t.Helper()
+
+	kr, err := NewKeyRing(primary, map[string][]byte{
+		"2024": bytes.Repeat([]byte{1}, 32),
+		"2025": bytes.Repeat([]byte{2}, 32),
+	})
+	if err != nil {
+		t.Fatalf("NewKeyRing() = %v", err)
+	}
+	kr, err = kr.WithBlindIndexKey(bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatalf("WithBlindIndexKey() = %v", err)
+	}
+	return kr
+}
+
+func TestEncryptedColumns(t *testing.T) {
+	t.Cleanup(func() { SetKeyRing(nil) })
+	SetKeyRing(keyRing(t, "2024"))
+
+	db, err := newSQLiteCnx(config.DatabaseConfig{Typo: "sqlite", Name: ":memory:"})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE clientes (id INTEGER, dni BLOB, dni_idx BLOB, foto BLOB)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+
+	dni := EncryptedString("12345678-9")
+	idx, err := dni.BlindIndex()
+	if err != nil {
+		t.Fatalf("BlindIndex() = %v", err)
+	}
+	if _, err := db.Exec(ctx, "INSERT INTO clientes VALUES (1, ?, ?, ?)", dni, idx, EncryptedBytes(nil)); err != nil {
+		t.Fatalf("Error al insertar: %v", err)
+	}
+
+	// El valor guardado no contiene el texto en claro
+	var raw []byte
+	if err := db.QueryRow(ctx, "SELECT dni FROM clientes").Scan(&raw); err != nil {
+		t.Fatalf("Error al consultar: %v", err)
+	}
+	if bytes.Contains(raw, []byte(dni)) {
+		t.Error("El valor guardado contiene el texto en claro")
+	}
+
+	// Búsqueda por igualdad con el índice ciego y descifrado con la llave anterior tras rotar
+	SetKeyRing(keyRing(t, "2025"))
+	idx2, _ := EncryptedString("12345678-9").BlindIndex()
+	var got EncryptedString
+	var foto EncryptedBytes
+	if err := db.QueryRow(ctx, "SELECT dni, foto FROM clientes WHERE dni_idx = ?", idx2).Scan(&got, &foto); err != nil {
+		t.Fatalf("Error al buscar por índice ciego: %v", err)
+	}
+	if got != dni || foto != nil {
+		t.Errorf("Se esperaba %q sin foto, obtuvo %q %v", dni, got, foto)
+	}
+	if !keyring.Load().NeedsRotation(raw) {
+		t.Error("Se esperaba que el valor cifrado con la llave anterior requiera rotación")
+	}
+}
+
+func TestEncryptedErrors(t *testing.T) {
+	t.Cleanup(func() { SetKeyRing(nil) })
+	SetKeyRing(nil)
+
+	if _, err := EncryptedString("x").Value(); !errors.Is(err, ErrNoKeyRing) {
+		t.Errorf("Value() = %v; se esperaba ErrNoKeyRing", err)
+	}
+
+	kr := keyRing(t, "2024")
+	data, _ := kr.Encrypt([]byte("x"))
+	SetKeyRing(mustKeyRing(t, "otra"))
+	var s EncryptedString
+	if err := s.Scan(data); err == nil {
+		t.Error("Se esperaba error al descifrar con una llave que no está en el llavero")
+	}
+
+	if _, err := NewKeyRing("2024", map[string][]byte{"2024": []byte("corta")}); err == nil {
+		t.Error("Se esperaba error con una llave de largo inválido")
+	}
+	if _, err := NewKeyRing("2026", map[string][]byte{"2024": bytes.Repeat([]byte{1}, 16)}); err == nil {
+		t.Error("Se esperaba error sin la llave primaria")
+	}
+}
+
+func mustKeyRing(t *testing.T, id string) *KeyRing {
+	t.Helper()
+	kr, err := NewKeyRing(id, map[string][]byte{id: bytes.Repeat([]byte{9}, 16)})
+	if err != nil {
+		t.Fatalf("NewKeyRing() = %v", err)
+	}
+	return kr
+}
+
+// TestEncryptedInterfaces valida que los tipos cifrados se integren con database/sql y con pgx
+func TestEncryptedInterfaces(t *testing.T) {
+	for _, v := range []any{new(EncryptedString), new(EncryptedBytes)} {
+		if _, ok := v.(sql.Scanner); !ok {
+			t.Errorf("%T no implementa sql.Scanner", v)
+		}
+		if _, ok := v.(pgtype.BytesScanner); !ok {
+			t.Errorf("%T no implementa pgtype.BytesScanner", v)
+		}
+	}
+	for _, v := range []any{EncryptedString(""), EncryptedBytes(nil)} {
+		if _, ok := v.(driver.Valuer); !ok {
+			t.Errorf("%T no implementa driver.Valuer", v)
+		}
+		if _, ok := v.(pgtype.BytesValuer); !ok {
+			t.Errorf("%T no implementa pgtype.BytesValuer", v)
+		}
+	}
+
+	// pgx debe cifrar con BytesValue y no codificar el texto subyacente
+	t.Cleanup(func() { SetKeyRing(nil) })
+	SetKeyRing(keyRing(t, "2024"))
+	m := pgtype.NewMap()
+	buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, EncryptedString("12345678-9"), nil)
+	if err != nil {
+		t.Fatalf("Encode() = %v", err)
+	}
+	var got EncryptedString
+	if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, buf, &got); err != nil || got != "12345678-9" {
+		t.Errorf("Scan() = %q, %v", got, err)
+	}
+}
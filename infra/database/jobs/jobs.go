@@ -0,0 +1,386 @@
+// Package jobs implementa una cola de trabajos en segundo plano guardada en una tabla de la base de datos,
+// sin necesidad de un broker. Los trabajos se encolan con Queue.Enqueue (dentro de la transacción del
+// contexto, si existe) y los procesan los Workers, que se ejecutan como service.Runner junto al servidor
+// HTTP. Cada trabajo se reintenta con espera exponencial hasta agotar sus intentos, y queda entonces en
+// estado `dead` para su revisión.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Valores por defecto de la cola
+const (
+	DefaultTable       = "vulcano_jobs"
+	DefaultMaxAttempts = 10
+)
+
+// PostgresDDL crea la tabla de trabajos en PostgreSQL
+const PostgresDDL = `CREATE TABLE IF NOT EXISTS vulcano_jobs (
+	id BIGSERIAL PRIMARY KEY,
+	kind VARCHAR(255) NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	unique_key VARCHAR(255) NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	max_attempts INT NOT NULL,
+	run_at TIMESTAMPTZ NOT NULL,
+	locked_until TIMESTAMPTZ NULL,
+	last_error TEXT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS ix_vulcano_jobs_ready ON vulcano_jobs (status, run_at);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_vulcano_jobs_unique_key ON vulcano_jobs (unique_key) WHERE status IN ('pending', 'running') AND unique_key IS NOT NULL;`
+
+// MSSQLDDL crea la tabla de trabajos en SQL Server. El índice único excluye los trabajos sin llave porque
+// SQL Server considera iguales los NULL: sin ese filtro solo podría haber un trabajo pendiente sin llave
+const MSSQLDDL = `CREATE TABLE vulcano_jobs (
+	id BIGINT IDENTITY(1,1) PRIMARY KEY,
+	kind NVARCHAR(255) NOT NULL,
+	payload NVARCHAR(MAX) NOT NULL,
+	status NVARCHAR(16) NOT NULL,
+	unique_key NVARCHAR(255) NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	max_attempts INT NOT NULL,
+	run_at DATETIME2 NOT NULL,
+	locked_until DATETIME2 NULL,
+	last_error NVARCHAR(MAX) NULL,
+	created_at DATETIME2 NOT NULL,
+	updated_at DATETIME2 NOT NULL
+);
+CREATE INDEX ix_vulcano_jobs_ready ON vulcano_jobs (status, run_at);
+CREATE UNIQUE INDEX ux_vulcano_jobs_unique_key ON vulcano_jobs (unique_key) WHERE status IN ('pending', 'running') AND unique_key IS NOT NULL;`
+
+// SQLiteDDL crea la tabla de trabajos en SQLite, pensada para desarrollo y pruebas
+const SQLiteDDL = `CREATE TABLE IF NOT EXISTS vulcano_jobs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	kind TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	status TEXT NOT NULL,
+	unique_key TEXT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL,
+	run_at TIMESTAMP NOT NULL,
+	locked_until TIMESTAMP NULL,
+	last_error TEXT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS ix_vulcano_jobs_ready ON vulcano_jobs (status, run_at);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_vulcano_jobs_unique_key ON vulcano_jobs (unique_key) WHERE status IN ('pending', 'running') AND unique_key IS NOT NULL`
+
+// ErrDuplicateJob indica que ya existe un trabajo pendiente o en curso con la misma llave única
+var ErrDuplicateJob = errors.New("ya existe un trabajo pendiente con la misma llave única")
+
+// tableRe valida el nombre de la tabla, que se interpola en las consultas
+var tableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Status es el estado de un trabajo
+type Status string
+
+const (
+	// Esperando su ejecución (o su reintento) en `run_at`
+	StatusPending Status = "pending"
+	// Tomado por un worker
+	StatusRunning Status = "running"
+	// Terminado con éxito
+	StatusDone Status = "done"
+	// Agotó sus intentos; se puede reintentar manualmente con Queue.Retry
+	StatusDead Status = "dead"
+	// Cancelado con Queue.Cancel antes de ejecutarse
+	StatusCancelled Status = "cancelled"
+)
+
+// @Description	Define un trabajo en segundo plano
+type Job struct {
+	// Identificador del trabajo
+	ID int64 `json:"id"                   example:"42"`
+	// Tipo de trabajo, asociado a un manejador
+	Kind string `json:"kind"                 example:"reporte.ventas"`
+	// Argumentos del trabajo en JSON
+	Payload json.RawMessage `json:"payload"              swaggertype:"object"`
+	// Estado: pending, running, done, dead o cancelled
+	Status Status `json:"status"               example:"pending"`
+	// Llave única, si se indicó al encolar
+	UniqueKey string `json:"unique_key,omitempty" example:"reporte-2024-01"`
+	// Intentos realizados
+	Attempts int `json:"attempts"             example:"1"`
+	// Intentos permitidos antes de pasar a `dead`
+	MaxAttempts int `json:"max_attempts"         example:"10"`
+	// Momento a partir del cual se puede ejecutar
+	RunAt time.Time `json:"run_at"`
+	// Error del último intento fallido
+	LastError string `json:"last_error,omitempty" example:"timeout al generar el reporte"`
+	// Momento en que se encoló
+	CreatedAt time.Time `json:"created_at"`
+	// Momento del último cambio de estado
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// columns son las columnas de Job, en el orden de scanJob
+const columns = "id, kind, payload, status, unique_key, attempts, max_attempts, run_at, last_error, created_at, updated_at"
+
+func scanJob(row database.Row) (Job, error) {
+	var j Job
+	var payload string
+	var uniqueKey, lastError *string
+	err := row.Scan(&j.ID, &j.Kind, &payload, &j.Status, &uniqueKey, &j.Attempts, &j.MaxAttempts, &j.RunAt, &lastError, &j.CreatedAt, &j.UpdatedAt)
+	j.Payload = json.RawMessage(payload)
+	if uniqueKey != nil {
+		j.UniqueKey = *uniqueKey
+	}
+	if lastError != nil {
+		j.LastError = *lastError
+	}
+	return j, err
+}
+
+// Queue encola y administra los trabajos de la tabla del motor indicado
+type Queue struct {
+	typo  string
+	table string
+}
+
+// Option configura la cola
+type Option func(*Queue)
+
+// WithTable cambia la tabla de trabajos (por defecto DefaultTable)
+func WithTable(table string) Option {
+	return func(q *Queue) {
+		q.table = table
+	}
+}
+
+// New crea la cola para el tipo de base de datos indicado (config.DatabaseConfig.Typo)
+func New(typo string, opts ...Option) (*Queue, error) {
+	q := &Queue{typo: typo, table: DefaultTable}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	switch typo {
+	case config.DatabaseTypePostgres, config.DatabaseTypeMssql, config.DatabaseTypeSqlite:
+	default:
+		return nil, fmt.Errorf("jobs: el tipo de base de datos `%s` no está soportado", typo)
+	}
+	if !tableRe.MatchString(q.table) {
+		return nil, fmt.Errorf("jobs: el nombre de tabla `%s` no es válido", q.table)
+	}
+	return q, nil
+}
+
+// EnqueueOption configura un trabajo al encolarlo
+type EnqueueOption func(*enqueueOptions)
+
+type enqueueOptions struct {
+	runAt       time.Time
+	uniqueKey   string
+	maxAttempts int
+}
+
+// RunAt programa el trabajo para ejecutarse a partir de `t`
+func RunAt(t time.Time) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = t
+	}
+}
+
+// Delay programa el trabajo para ejecutarse después de `d`
+func Delay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = time.Now().Add(d)
+	}
+}
+
+// UniqueKey evita encolar el trabajo si ya hay otro pendiente o en curso con la misma llave; en ese caso
+// Enqueue devuelve ErrDuplicateJob
+func UniqueKey(key string) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.uniqueKey = key
+	}
+}
+
+// MaxAttempts cambia la cantidad de intentos del trabajo (por defecto DefaultMaxAttempts)
+func MaxAttempts(n int) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// Enqueue encola un trabajo del tipo `kind` con los argumentos `args`, serializados a JSON, y devuelve su
+// identificador. Usa database.From, de modo que dentro de una transacción el trabajo solo se encola si
+// esta se confirma
+func (q *Queue) Enqueue(ctx context.Context, kind string, args any, opts ...EnqueueOption) (int64, error) {
+	if kind == "" {
+		return 0, errors.New("jobs: el tipo de trabajo es obligatorio")
+	}
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return 0, fmt.Errorf("jobs: no se pudieron serializar los argumentos: %w", err)
+	}
+
+	now := time.Now().UTC()
+	o := enqueueOptions{runAt: now, maxAttempts: DefaultMaxAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxAttempts < 1 {
+		return 0, errors.New("jobs: la cantidad de intentos debe ser mayor a 0")
+	}
+
+	var uniqueKey any
+	if o.uniqueKey != "" {
+		uniqueKey = o.uniqueKey
+	}
+	args2 := []any{kind, string(payload), string(StatusPending), uniqueKey, o.maxAttempts, o.runAt.UTC(), now, now}
+
+	const insert = "(kind, payload, status, unique_key, max_attempts, run_at, created_at, updated_at)"
+	const exists = "SELECT 1 FROM %s%s WHERE unique_key = ? AND status IN ('pending', 'running')"
+
+	var query string
+	switch q.typo {
+	case config.DatabaseTypePostgres:
+		query = "INSERT INTO " + q.table + " " + insert + " VALUES (?, ?, ?, ?, ?, ?, ?, ?)" +
+			" ON CONFLICT (unique_key) WHERE status IN ('pending', 'running') AND unique_key IS NOT NULL DO NOTHING RETURNING id"
+	case config.DatabaseTypeMssql:
+		query = "INSERT INTO " + q.table + " " + insert + " OUTPUT INSERTED.id SELECT ?, ?, ?, ?, ?, ?, ?, ?" +
+			" WHERE NOT EXISTS (" + fmt.Sprintf(exists, q.table, " WITH (UPDLOCK, HOLDLOCK)") + ")"
+		args2 = append(args2, uniqueKey)
+	default:
+		query = "INSERT INTO " + q.table + " " + insert + " SELECT ?, ?, ?, ?, ?, ?, ?, ?" +
+			" WHERE NOT EXISTS (" + fmt.Sprintf(exists, q.table, "") + ") RETURNING id"
+		args2 = append(args2, uniqueKey)
+	}
+
+	var id int64
+	err = database.From(ctx).QueryRow(ctx, database.Rebind(q.typo, query), args2...).Scan(&id)
+	if database.IsNoRows(err) {
+		return 0, ErrDuplicateJob
+	}
+	return id, err
+}
+
+// --- Administración ---
+
+// Filter filtra el listado de trabajos; los campos vacíos no filtran
+type Filter struct {
+	Status Status
+	Kind   string
+	// Cantidad máxima de trabajos (por defecto 100)
+	Limit int
+	// Trabajos a omitir, para paginar
+	Offset int
+}
+
+// List devuelve los trabajos más recientes que cumplen el filtro
+func (q *Queue) List(ctx context.Context, f Filter) ([]Job, error) {
+	var where []string
+	var args []any
+	if f.Status != "" {
+		where, args = append(where, "status = ?"), append(args, string(f.Status))
+	}
+	if f.Kind != "" {
+		where, args = append(where, "kind = ?"), append(args, f.Kind)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := "SELECT " + columns + " FROM " + q.table
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if q.typo == config.DatabaseTypeMssql {
+		query += fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", max(f.Offset, 0), limit)
+	} else {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, max(f.Offset, 0))
+	}
+
+	return database.Collect(database.Stream(ctx, database.From(ctx), scanJob, database.Rebind(q.typo, query), args...))
+}
+
+// Get devuelve un trabajo; si no existe devuelve un error mistake.NotFound
+func (q *Queue) Get(ctx context.Context, id int64) (*Job, error) {
+	query := database.Rebind(q.typo, "SELECT "+columns+" FROM "+q.table+" WHERE id = ?")
+	j, err := scanJob(database.From(ctx).QueryRow(ctx, query, id))
+	if database.IsNoRows(err) {
+		return nil, notFound(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Retry vuelve a encolar para ejecución inmediata un trabajo `dead` o `cancelled`, con sus intentos en
+// cero. Los trabajos en otro estado se rechazan con mistake.Invalid, y los que tienen la misma llave única
+// que otro trabajo pendiente o en curso con mistake.Duplicated
+func (q *Queue) Retry(ctx context.Context, id int64) error {
+	hint := ""
+	if q.typo == config.DatabaseTypeMssql {
+		hint = " WITH (UPDLOCK, HOLDLOCK)"
+	}
+
+	now := time.Now().UTC()
+	query := "UPDATE " + q.table + " SET status = 'pending', attempts = 0, run_at = ?, locked_until = NULL, updated_at = ?" +
+		" WHERE id = ? AND status IN ('dead', 'cancelled') AND (unique_key IS NULL OR NOT EXISTS (SELECT 1 FROM " + q.table + " o" + hint +
+		" WHERE o.unique_key = " + q.table + ".unique_key AND o.status IN ('pending', 'running')))"
+	n, err := database.From(ctx).Exec(ctx, database.Rebind(q.typo, query), now, now, id)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	j, err := q.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if j.Status == StatusDead || j.Status == StatusCancelled {
+		// El estado permitía reintentar: lo impidió otro trabajo con la misma llave
+		return mistake.New(mistake.Duplicated, fmt.Sprintf("no se puede reintentar el trabajo %d: %s", id, ErrDuplicateJob), ErrDuplicateJob, "unique_key")
+	}
+	return invalidState("reintentar", j)
+}
+
+// Cancel cancela un trabajo pendiente. Los trabajos en otro estado (incluidos los que están en curso) se
+// rechazan con mistake.Invalid
+func (q *Queue) Cancel(ctx context.Context, id int64) error {
+	query := "UPDATE " + q.table + " SET status = 'cancelled', updated_at = ? WHERE id = ? AND status = 'pending'"
+	return q.transition(ctx, id, "cancelar", query, time.Now().UTC(), id)
+}
+
+// transition ejecuta un cambio de estado condicionado; si no afecta al trabajo distingue si no existe o si
+// su estado no lo permite
+func (q *Queue) transition(ctx context.Context, id int64, action, query string, args ...any) error {
+	n, err := database.From(ctx).Exec(ctx, database.Rebind(q.typo, query), args...)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	j, err := q.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return invalidState(action, j)
+}
+
+// invalidState indica que el estado del trabajo no permite la acción
+func invalidState(action string, j *Job) error {
+	return mistake.New(mistake.Invalid, fmt.Sprintf("no se puede %s el trabajo %d en estado `%s`", action, j.ID, j.Status), errors.New("estado no válido"), "status")
+}
+
+func notFound(id int64) error {
+	return mistake.New(mistake.NotFound, fmt.Sprintf("no existe el trabajo %d", id), errors.New("trabajo no encontrado"))
+}
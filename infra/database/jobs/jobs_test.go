@@ -0,0 +1,249 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+type reporte struct {
+	Mes string `json:"mes"`
+}
+
+// jobsDB crea una base SQLite en memoria con la tabla de trabajos y la deja en el contexto
+func jobsDB(t *testing.T) (context.Context, database.Database, *Queue) {
+	t.Helper()
+
+	db, err := database.Open(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	ctx := database.WithDatabase(context.Background(), db)
+	if _, err := db.Exec(ctx, SQLiteDDL); err != nil {
+		t.Fatalf("Error al crear la tabla de trabajos: %v", err)
+	}
+
+	q, err := New(config.DatabaseTypeSqlite)
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	return ctx, db, q
+}
+
+func code(err error) int {
+	var mk *mistake.Mistake
+	if errors.As(err, &mk) {
+		return mk.Code()
+	}
+	return 0
+}
+
+func TestEnqueue(t *testing.T) {
+	ctx, _, q := jobsDB(t)
+
+	id, err := q.Enqueue(ctx, "reporte", reporte{Mes: "2024-01"}, UniqueKey("reporte-2024-01"), MaxAttempts(3))
+	if err != nil {
+		t.Fatalf("Enqueue() = %v", err)
+	}
+	if _, err := q.Enqueue(ctx, "reporte", reporte{Mes: "2024-01"}, UniqueKey("reporte-2024-01")); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("Enqueue() con llave repetida = %v; se esperaba ErrDuplicateJob", err)
+	}
+
+	j, err := q.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if j.Status != StatusPending || j.UniqueKey != "reporte-2024-01" || j.MaxAttempts != 3 || string(j.Payload) != `{"mes":"2024-01"}` {
+		t.Errorf("Trabajo inesperado: %+v", j)
+	}
+
+	// Cancelado, la llave queda libre
+	if err := q.Cancel(ctx, id); err != nil {
+		t.Fatalf("Cancel() = %v", err)
+	}
+	if _, err := q.Enqueue(ctx, "reporte", reporte{Mes: "2024-01"}, UniqueKey("reporte-2024-01")); err != nil {
+		t.Errorf("Enqueue() tras cancelar = %v", err)
+	}
+
+	// Encolado dentro de una transacción revertida no se registra
+	_ = database.RunInTx(ctx, func(ctx context.Context) error {
+		if _, err := q.Enqueue(ctx, "reporte", reporte{Mes: "2024-02"}); err != nil {
+			return err
+		}
+		return errors.New("falla de negocio")
+	})
+	if jobs, _ := q.List(ctx, Filter{Kind: "reporte"}); len(jobs) != 2 {
+		t.Errorf("Se esperaban 2 trabajos, obtuvo %d", len(jobs))
+	}
+
+	// Los trabajos sin llave única no se consideran duplicados entre sí
+	for range 2 {
+		if _, err := q.Enqueue(ctx, "correo", reporte{Mes: "2024-03"}); err != nil {
+			t.Errorf("Enqueue() sin llave = %v", err)
+		}
+	}
+	if jobs, _ := q.List(ctx, Filter{Kind: "correo", Status: StatusPending}); len(jobs) != 2 {
+		t.Errorf("Se esperaban 2 trabajos sin llave, obtuvo %d", len(jobs))
+	}
+
+	// Reintentar un trabajo cuya llave ya usa otro trabajo pendiente responde Duplicated
+	if err := q.Retry(ctx, id); code(err) != http.StatusConflict {
+		t.Errorf("Retry() con la llave en uso = %v; se esperaba Duplicated", err)
+	}
+	if j, _ := q.Get(ctx, id); j.Status != StatusCancelled {
+		t.Errorf("Se esperaba el trabajo cancelado tras el Retry() rechazado, obtuvo %s", j.Status)
+	}
+
+	if _, err := New(config.DatabaseTypeSqlite, WithTable("jobs; DROP")); err == nil {
+		t.Error("Se esperaba error con un nombre de tabla inválido")
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	ctx, _, q := jobsDB(t)
+
+	a, _ := q.Enqueue(ctx, "reporte", reporte{})
+	b, _ := q.Enqueue(ctx, "correo", reporte{})
+
+	if _, err := q.Get(ctx, 99); code(err) != http.StatusNotFound {
+		t.Errorf("Get() inexistente = %v; se esperaba NotFound", err)
+	}
+	if err := q.Retry(ctx, a); code(err) != http.StatusBadRequest {
+		t.Errorf("Retry() de un trabajo pendiente = %v; se esperaba Invalid", err)
+	}
+	if err := q.Cancel(ctx, 99); code(err) != http.StatusNotFound {
+		t.Errorf("Cancel() inexistente = %v; se esperaba NotFound", err)
+	}
+
+	if err := q.Cancel(ctx, b); err != nil {
+		t.Fatalf("Cancel() = %v", err)
+	}
+	if err := q.Cancel(ctx, b); code(err) != http.StatusBadRequest {
+		t.Errorf("Cancel() de un trabajo cancelado = %v; se esperaba Invalid", err)
+	}
+
+	cancelled, err := q.List(ctx, Filter{Status: StatusCancelled})
+	if err != nil || len(cancelled) != 1 || cancelled[0].ID != b {
+		t.Errorf("List(cancelled) = %v, %v", cancelled, err)
+	}
+
+	if err := q.Retry(ctx, b); err != nil {
+		t.Fatalf("Retry() = %v", err)
+	}
+	if j, _ := q.Get(ctx, b); j.Status != StatusPending {
+		t.Errorf("Se esperaba el trabajo pendiente tras Retry(), obtuvo %s", j.Status)
+	}
+
+	page, _ := q.List(ctx, Filter{Limit: 1, Offset: 1})
+	if len(page) != 1 || page[0].ID != a {
+		t.Errorf("List() paginado = %v", page)
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	ctx, db, q := jobsDB(t)
+
+	var calls atomic.Int32
+	w := NewWorkers(db, q, WithPollInterval(10*time.Millisecond), WithBackoff(time.Millisecond, time.Millisecond))
+	Register(w, "reporte", func(ctx context.Context, job Job, args reporte) error {
+		calls.Add(1)
+		switch args.Mes {
+		case "falla":
+			return errors.New("sin datos")
+		case "invalido":
+			return Permanent(errors.New("mes inválido"))
+		case "panic":
+			panic("fuera de rango")
+		}
+		return nil
+	})
+
+	ok, _ := q.Enqueue(ctx, "reporte", reporte{Mes: "2024-01"})
+	retried, _ := q.Enqueue(ctx, "reporte", reporte{Mes: "falla"}, MaxAttempts(3))
+	permanent, _ := q.Enqueue(ctx, "reporte", reporte{Mes: "invalido"})
+	panicked, _ := q.Enqueue(ctx, "reporte", reporte{Mes: "panic"}, MaxAttempts(1))
+	undecodable, _ := q.Enqueue(ctx, "reporte", "no es un objeto")
+	later, _ := q.Enqueue(ctx, "reporte", reporte{Mes: "2024-02"}, Delay(time.Hour))
+	other, _ := q.Enqueue(ctx, "correo", reporte{})
+
+	go func() { _ = w.Start() }()
+
+	wantStatus := map[int64]Status{ok: StatusDone, retried: StatusDead, permanent: StatusDead, panicked: StatusDead, undecodable: StatusDead}
+	deadline := time.Now().Add(5 * time.Second)
+	for id, want := range wantStatus {
+		for {
+			j, err := q.Get(ctx, id)
+			if err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			if j.Status == want {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("El trabajo %d quedó en %s; se esperaba %s", id, j.Status, want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	if err := w.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() = %v", err)
+	}
+
+	if j, _ := q.Get(ctx, retried); j.Attempts != 3 || j.LastError != "sin datos" {
+		t.Errorf("Se esperaban 3 intentos con el último error, obtuvo %d %q", j.Attempts, j.LastError)
+	}
+	if j, _ := q.Get(ctx, permanent); j.Attempts != 1 {
+		t.Errorf("Un error definitivo no se debe reintentar, obtuvo %d intentos", j.Attempts)
+	}
+	for _, id := range []int64{later, other} {
+		if j, _ := q.Get(ctx, id); j.Status != StatusPending {
+			t.Errorf("El trabajo %d (%s) no debía ejecutarse, quedó en %s", id, j.Kind, j.Status)
+		}
+	}
+	// ok + 3 intentos de `falla` + invalido + panic (los argumentos no decodificables no llegan al manejador)
+	if n := calls.Load(); n != 6 {
+		t.Errorf("Se esperaban 6 ejecuciones, obtuvo %d", n)
+	}
+
+	if err := NewWorkers(db, q, WithLease(0)).Start(); err == nil {
+		t.Error("Se esperaba error con un plazo de reserva de 0")
+	}
+}
+
+func TestWorkersShutdown(t *testing.T) {
+	ctx, db, q := jobsDB(t)
+
+	started := make(chan struct{})
+	w := NewWorkers(db, q, WithPollInterval(10*time.Millisecond))
+	Register(w, "lento", func(ctx context.Context, job Job, _ struct{}) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	id, _ := q.Enqueue(ctx, "lento", struct{}{})
+	go func() { _ = w.Start() }()
+	<-started
+
+	sctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := w.Shutdown(sctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v; se esperaba DeadlineExceeded", err)
+	}
+
+	// El trabajo interrumpido vuelve a la cola sin consumir el intento
+	j, _ := q.Get(ctx, id)
+	if j.Status != StatusPending || j.Attempts != 0 {
+		t.Errorf("Se esperaba el trabajo pendiente sin intentos, obtuvo %s con %d", j.Status, j.Attempts)
+	}
+}
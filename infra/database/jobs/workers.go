@@ -0,0 +1,396 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Valores por defecto de los Workers
+const (
+	DefaultConcurrency  = 10
+	DefaultPollInterval = time.Second
+	DefaultLease        = 5 * time.Minute
+	DefaultMinBackoff   = 5 * time.Second
+	DefaultMaxBackoff   = time.Hour
+)
+
+// finishTimeout es el tiempo máximo para registrar el resultado de un trabajo, incluso durante el apagado
+const finishTimeout = 5 * time.Second
+
+// Handler procesa un trabajo con sus argumentos ya decodificados. Un error programa un reintento, salvo
+// que se envuelva con Permanent
+type Handler[T any] func(ctx context.Context, job Job, args T) error
+
+type handler func(ctx context.Context, job Job) error
+
+// permanentError marca un error que no se debe reintentar
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marca el error como definitivo: el trabajo pasa a `dead` sin agotar sus intentos
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// WorkersOption configura los Workers
+type WorkersOption func(*Workers)
+
+// WithConcurrency cambia la cantidad máxima de trabajos ejecutados en paralelo por la instancia
+func WithConcurrency(n int) WorkersOption {
+	return func(w *Workers) {
+		w.concurrency = n
+	}
+}
+
+// WithPollInterval cambia el intervalo de consulta de la tabla cuando no hay trabajos listos
+func WithPollInterval(d time.Duration) WorkersOption {
+	return func(w *Workers) {
+		w.pollInterval = d
+	}
+}
+
+// WithLease cambia la reserva de un trabajo en curso. Mientras se ejecuta se renueva periódicamente; si
+// el proceso termina sin liberarlo, otra instancia lo retoma al vencer la reserva
+func WithLease(d time.Duration) WorkersOption {
+	return func(w *Workers) {
+		w.lease = d
+	}
+}
+
+// WithBackoff cambia la espera entre reintentos, que se duplica en cada fallo desde `minWait` hasta `maxWait`
+func WithBackoff(minWait, maxWait time.Duration) WorkersOption {
+	return func(w *Workers) {
+		w.minBackoff, w.maxBackoff = minWait, maxWait
+	}
+}
+
+// Workers ejecuta los trabajos de la cola con los manejadores registrados. Implementa service.Runner para
+// ejecutarse junto al servidor HTTP. Varias instancias pueden trabajar sobre la misma tabla: los trabajos
+// se reservan con `FOR UPDATE SKIP LOCKED` en PostgreSQL y `UPDLOCK, READPAST` en SQL Server
+type Workers struct {
+	db    database.Database
+	queue *Queue
+
+	concurrency  int
+	pollInterval time.Duration
+	lease        time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]handler
+
+	active   atomic.Int32
+	running  sync.WaitGroup
+	wake     chan struct{}
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewWorkers crea los Workers que ejecutan los trabajos de `q`, leídos desde `db`
+func NewWorkers(db database.Database, q *Queue, opts ...WorkersOption) *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Workers{
+		db:           db,
+		queue:        q,
+		concurrency:  DefaultConcurrency,
+		pollInterval: DefaultPollInterval,
+		lease:        DefaultLease,
+		minBackoff:   DefaultMinBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+		handlers:     map[string]handler{},
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Register asocia el manejador `h` a los trabajos del tipo `kind`. Los argumentos del trabajo se decodifican
+// desde JSON a `T`; si no se pueden decodificar el trabajo pasa a `dead`. Solo se toman de la cola los tipos
+// registrados, de modo que distintos procesos pueden atender distintos tipos
+func Register[T any](w *Workers, kind string, h Handler[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[kind] = func(ctx context.Context, job Job) error {
+		var args T
+		if err := json.Unmarshal(job.Payload, &args); err != nil {
+			return Permanent(fmt.Errorf("no se pudieron decodificar los argumentos: %w", err))
+		}
+		return h(ctx, job, args)
+	}
+}
+
+// Start ejecuta los trabajos hasta que se invoque Shutdown
+func (w *Workers) Start() error {
+	if !w.started.CompareAndSwap(false, true) {
+		return errors.New("jobs: los workers ya están iniciados")
+	}
+	defer close(w.done)
+	if w.concurrency < 1 {
+		return errors.New("jobs: la concurrencia debe ser mayor a 0")
+	}
+	if w.lease <= 0 {
+		return errors.New("jobs: el plazo de reserva debe ser mayor a 0")
+	}
+	if len(w.kinds()) == 0 {
+		return errors.New("jobs: no hay manejadores registrados")
+	}
+	slog.Info("Workers de trabajos iniciados", slog.String("table", w.queue.table), slog.Int("concurrency", w.concurrency))
+
+	for {
+		wait := w.pollInterval
+		if free := w.concurrency - int(w.active.Load()); free > 0 {
+			n, err := w.RunOnce(w.ctx, free)
+			if err != nil && w.ctx.Err() == nil {
+				slog.Warn("Error al tomar los trabajos pendientes", slog.String("error", err.Error()))
+			}
+			// Con todos los cupos ocupados puede haber más trabajos listos
+			if err == nil && n == free {
+				wait = 0
+			}
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-w.stop:
+			t.Stop()
+			w.running.Wait()
+			return nil
+		case <-w.wake:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
+
+// Shutdown deja de tomar trabajos y espera a que terminen los que están en curso. Si `ctx` termina antes,
+// se cancelan y vuelven a la cola sin consumir un intento
+func (w *Workers) Shutdown(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	if !w.started.Load() {
+		return nil
+	}
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+// RunOnce toma hasta `limit` trabajos listos y los ejecuta en segundo plano. Devuelve la cantidad tomada
+func (w *Workers) RunOnce(ctx context.Context, limit int) (int, error) {
+	jobs, err := w.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		w.active.Add(1)
+		w.running.Add(1)
+		go func() {
+			defer w.running.Done()
+			defer func() {
+				w.active.Add(-1)
+				select {
+				case w.wake <- struct{}{}:
+				default:
+				}
+			}()
+			w.execute(job)
+		}()
+	}
+	return len(jobs), nil
+}
+
+func (w *Workers) kinds() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Sorted(maps.Keys(w.handlers))
+}
+
+// claim reserva los trabajos listos: pendientes con `run_at` vencido, o en curso con la reserva vencida
+// (su proceso terminó sin liberarlos)
+func (w *Workers) claim(ctx context.Context, limit int) ([]Job, error) {
+	kinds := w.kinds()
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	table := w.queue.table
+	now := time.Now().UTC()
+
+	where := "kind IN (?" + strings.Repeat(", ?", len(kinds)-1) + ")" +
+		" AND ((status = 'pending' AND run_at <= ?) OR (status = 'running' AND locked_until < ?))"
+	whereArgs := append(toAny(kinds), now, now)
+	set := "status = 'running', attempts = attempts + 1, locked_until = ?, updated_at = ?"
+	setArgs := []any{now.Add(w.lease), now}
+
+	var query string
+	var args []any
+	switch w.queue.typo {
+	case config.DatabaseTypeMssql:
+		output := "INSERTED." + strings.ReplaceAll(columns, ", ", ", INSERTED.")
+		query = "WITH c AS (SELECT TOP (?) * FROM " + table + " WITH (UPDLOCK, READPAST, ROWLOCK) WHERE " + where + " ORDER BY run_at, id)" +
+			" UPDATE c SET " + set + " OUTPUT " + output
+		args = slices.Concat([]any{limit}, whereArgs, setArgs)
+	default:
+		lock := ""
+		if w.queue.typo == config.DatabaseTypePostgres {
+			lock = " FOR UPDATE SKIP LOCKED"
+		}
+		query = "UPDATE " + table + " SET " + set + " WHERE id IN (SELECT id FROM " + table + " WHERE " + where +
+			" ORDER BY run_at, id LIMIT ?" + lock + ") RETURNING " + columns
+		args = slices.Concat(setArgs, whereArgs, []any{limit})
+	}
+
+	return database.Collect(database.Stream(ctx, w.db, scanJob, database.Rebind(w.queue.typo, query), args...))
+}
+
+// execute ejecuta el trabajo renovando su reserva y registra el resultado
+func (w *Workers) execute(job Job) {
+	w.mu.RLock()
+	h := w.handlers[job.Kind]
+	w.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(w.ctx)
+	heartbeat := make(chan struct{})
+	go func() {
+		defer close(heartbeat)
+		w.heartbeat(ctx, job.ID)
+	}()
+
+	err := safeCall(ctx, h, job)
+	cancel()
+	<-heartbeat
+
+	// El resultado se registra aunque los workers se estén apagando
+	fctx, fcancel := context.WithTimeout(context.Background(), finishTimeout)
+	defer fcancel()
+
+	if err != nil && w.ctx.Err() != nil {
+		slog.Info("Trabajo interrumpido por el apagado; vuelve a la cola", slog.Int64("id", job.ID), slog.String("kind", job.Kind))
+		err = w.release(fctx, job.ID)
+	} else {
+		err = w.finish(fctx, job, err)
+	}
+	if err != nil {
+		slog.Error("No se pudo registrar el resultado del trabajo", slog.Int64("id", job.ID), slog.String("error", err.Error()))
+	}
+}
+
+// safeCall invoca el manejador convirtiendo un panic en un error
+func safeCall(ctx context.Context, h handler, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, job)
+}
+
+// heartbeat renueva la reserva del trabajo hasta que termine `ctx`
+func (w *Workers) heartbeat(ctx context.Context, id int64) {
+	t := time.NewTicker(w.lease / 3)
+	defer t.Stop()
+
+	query := database.Rebind(w.queue.typo, "UPDATE "+w.queue.table+" SET locked_until = ? WHERE id = ? AND status = 'running'")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := w.db.Exec(ctx, query, time.Now().UTC().Add(w.lease), id); err != nil && ctx.Err() == nil {
+				slog.Warn("No se pudo renovar la reserva del trabajo", slog.Int64("id", id), slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// finish registra el resultado del trabajo: `done` si no hubo error, y si lo hubo un reintento con espera
+// o `dead` si agotó sus intentos o el error es definitivo
+func (w *Workers) finish(ctx context.Context, job Job, jobErr error) error {
+	now := time.Now().UTC()
+	table := w.queue.table
+
+	if jobErr == nil {
+		query := "UPDATE " + table + " SET status = 'done', locked_until = NULL, last_error = NULL, updated_at = ? WHERE id = ? AND status = 'running'"
+		_, err := w.db.Exec(ctx, database.Rebind(w.queue.typo, query), now, job.ID)
+		return err
+	}
+
+	var permanent *permanentError
+	if errors.As(jobErr, &permanent) || job.Attempts >= job.MaxAttempts {
+		slog.Error("El trabajo agotó sus intentos",
+			slog.Int64("id", job.ID), slog.String("kind", job.Kind), slog.Int("attempt", job.Attempts), slog.String("error", jobErr.Error()))
+
+		query := "UPDATE " + table + " SET status = 'dead', locked_until = NULL, last_error = ?, updated_at = ? WHERE id = ? AND status = 'running'"
+		_, err := w.db.Exec(ctx, database.Rebind(w.queue.typo, query), jobErr.Error(), now, job.ID)
+		return err
+	}
+
+	slog.Warn("El trabajo falló; se reintentará",
+		slog.Int64("id", job.ID), slog.String("kind", job.Kind), slog.Int("attempt", job.Attempts), slog.String("error", jobErr.Error()))
+
+	query := "UPDATE " + table + " SET status = 'pending', run_at = ?, locked_until = NULL, last_error = ?, updated_at = ? WHERE id = ? AND status = 'running'"
+	_, err := w.db.Exec(ctx, database.Rebind(w.queue.typo, query), now.Add(w.retryIn(job.Attempts-1)), jobErr.Error(), now, job.ID)
+	return err
+}
+
+// release devuelve a la cola un trabajo interrumpido, sin contar el intento
+func (w *Workers) release(ctx context.Context, id int64) error {
+	query := "UPDATE " + w.queue.table + " SET status = 'pending', attempts = attempts - 1, locked_until = NULL, updated_at = ? WHERE id = ? AND status = 'running'"
+	_, err := w.db.Exec(ctx, database.Rebind(w.queue.typo, query), time.Now().UTC(), id)
+	return err
+}
+
+// retryIn devuelve la espera antes del siguiente intento tras `attempts` intentos fallidos previos
+func (w *Workers) retryIn(attempts int) time.Duration {
+	wait := w.minBackoff
+	for range attempts {
+		if wait >= w.maxBackoff {
+			break
+		}
+		wait *= 2
+	}
+	return min(wait, w.maxBackoff)
+}
+
+func toAny[T any](s []T) []any {
+	out := make([]any, len(s))
+	for i, v := range s {
+		out[i] = v
+	}
+	return out
+}
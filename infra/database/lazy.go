@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+// ErrNotReady indica que la conexión diferida todavía no se estableció
+var ErrNotReady = errors.New("la base de datos todavía no está disponible")
+
+// Ready devuelve nil si la conexión global está lista para usarse. Solo la conexión diferida (ver
+// config.DatabaseConfig.Lazy) puede no estarlo; sin conexión configurada se considera lista
+func Ready() error {
+	if r, ok := cnx.(interface{ Ready() error }); ok {
+		return r.Ready()
+	}
+	return nil
+}
+
+// lazyDatabase establece la conexión en segundo plano, reintentando con espera exponencial, y delega en
+// ella una vez disponible. Los pools de cada motor se encargan de las reconexiones posteriores
+type lazyDatabase struct {
+	mu      sync.RWMutex
+	db      Database
+	lastErr error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newLazy(open func() (Database, error)) *lazyDatabase {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &lazyDatabase{lastErr: ErrNotReady, cancel: cancel, done: make(chan struct{})}
+	go l.connect(ctx, open)
+	return l
+}
+
+func (l *lazyDatabase) connect(ctx context.Context, open func() (Database, error)) {
+	defer close(l.done)
+
+	wait := retryMinBackoff
+	for {
+		db, err := open()
+		if err == nil {
+			l.mu.Lock()
+			l.db, l.lastErr = db, nil
+			l.mu.Unlock()
+			slog.Info("Conexión a la base de datos establecida")
+			return
+		}
+
+		l.mu.Lock()
+		l.lastErr = err
+		l.mu.Unlock()
+		slog.Warn("No se pudo conectar a la base de datos", slog.String("error", err.Error()), slog.String("retry_in", wait.String()))
+
+		var ok bool
+		if wait, ok = backoff(ctx, wait); !ok {
+			return
+		}
+	}
+}
+
+// current devuelve la conexión establecida o un error 503 si todavía no lo está
+func (l *lazyDatabase) current() (Database, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.db == nil {
+		return nil, mistake.New(mistake.Unavailable, ErrNotReady.Error(), l.lastErr)
+	}
+	return l.db, nil
+}
+
+// Ready devuelve nil cuando la conexión está establecida, o la causa del último intento fallido
+func (l *lazyDatabase) Ready() error {
+	_, err := l.current()
+	return err
+}
+
+func (l *lazyDatabase) Close() {
+	l.cancel()
+	<-l.done
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.db != nil {
+		l.db.Close()
+	}
+}
+
+func (l *lazyDatabase) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	db, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	return db.Query(ctx, query, args...)
+}
+
+func (l *lazyDatabase) QueryRow(ctx context.Context, query string, args ...any) Row {
+	db, err := l.current()
+	if err != nil {
+		return errRow{err}
+	}
+	return db.QueryRow(ctx, query, args...)
+}
+
+func (l *lazyDatabase) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	db, err := l.current()
+	if err != nil {
+		return 0, err
+	}
+	return db.Exec(ctx, query, args...)
+}
+
+func (l *lazyDatabase) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	db, err := l.current()
+	if err != nil {
+		closeSource(src)
+		return 0, err
+	}
+	return db.BulkInsert(ctx, table, columns, src, opts...)
+}
+
+func (l *lazyDatabase) BeginTx(ctx context.Context) (Tx, error) {
+	db, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	return db.BeginTx(ctx)
+}
+
+// RawConnection devuelve la conexión del motor, o nil si todavía no se estableció
+func (l *lazyDatabase) RawConnection() any {
+	db, err := l.current()
+	if err != nil {
+		return nil
+	}
+	return db.RawConnection()
+}
+
+// --- Capacidades opcionales del motor ---
+
+func (l *lazyDatabase) Listen(ctx context.Context, channels ...string) (<-chan Notification, error) {
+	db, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	n, ok := db.(Notifier)
+	if !ok {
+		return nil, errors.New("notificaciones: el motor no soporta notificaciones")
+	}
+	return n.Listen(ctx, channels...)
+}
+
+func (l *lazyDatabase) Notify(ctx context.Context, channel, payload string) error {
+	db, err := l.current()
+	if err != nil {
+		return err
+	}
+	n, ok := db.(Notifier)
+	if !ok {
+		return errors.New("notificaciones: el motor no soporta notificaciones")
+	}
+	return n.Notify(ctx, channel, payload)
+}
+
+func (l *lazyDatabase) CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error) {
+	db, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	return CallProcedure(ctx, db, name, params...)
+}
+
+func (l *lazyDatabase) TryLock(ctx context.Context, name string) (*Lock, bool, error) {
+	locker, err := l.locker()
+	if err != nil {
+		return nil, false, err
+	}
+	return locker.TryLock(ctx, name)
+}
+
+func (l *lazyDatabase) Lock(ctx context.Context, name string, timeout time.Duration) (*Lock, error) {
+	locker, err := l.locker()
+	if err != nil {
+		return nil, err
+	}
+	return locker.Lock(ctx, name, timeout)
+}
+
+func (l *lazyDatabase) locker() (Locker, error) {
+	db, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	locker, ok := db.(Locker)
+	if !ok {
+		return nil, errors.New("bloqueos: el motor no soporta bloqueos con nombre")
+	}
+	return locker, nil
+}
+
+func (l *lazyDatabase) Tables(ctx context.Context, schema string) ([]TableRef, error) {
+	in, err := l.inspector()
+	if err != nil {
+		return nil, err
+	}
+	return in.Tables(ctx, schema)
+}
+
+func (l *lazyDatabase) DescribeTable(ctx context.Context, schema, table string) (*Table, error) {
+	in, err := l.inspector()
+	if err != nil {
+		return nil, err
+	}
+	return in.DescribeTable(ctx, schema, table)
+}
+
+func (l *lazyDatabase) inspector() (Inspector, error) {
+	db, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	in, ok := db.(Inspector)
+	if !ok {
+		return nil, errors.New("esquema: el motor no soporta la lectura del esquema")
+	}
+	return in, nil
+}
+
+// errRow es una fila que solo devuelve un error al escanearse
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+func TestLazyDatabaseConnects(t *testing.T) {
+	release := make(chan struct{})
+	l := newLazy(func() (Database, error) {
+		<-release
+		return newSQLiteCnx(config.DatabaseConfig{Typo: "sqlite", Name: ":memory:"})
+	})
+	defer l.Close()
+
+	err := l.Ready()
+	var mk *mistake.Mistake
+	if !errors.As(err, &mk) || mk.Code() != http.StatusServiceUnavailable {
+		t.Fatalf("Ready() = %v; se esperaba un error 503", err)
+	}
+	if _, err := l.Exec(context.Background(), "SELECT 1"); !errors.As(err, &mk) {
+		t.Fatalf("Exec() = %v; se esperaba fallar de inmediato", err)
+	}
+	if err := l.QueryRow(context.Background(), "SELECT 1").Scan(new(int)); err == nil {
+		t.Fatal("se esperaba un error en QueryRow antes de conectar")
+	}
+
+	close(release)
+	<-l.done
+
+	if err := l.Ready(); err != nil {
+		t.Fatalf("Ready() = %v después de conectar", err)
+	}
+	var n int
+	if err := l.QueryRow(context.Background(), "SELECT 1").Scan(&n); err != nil || n != 1 {
+		t.Fatalf("QueryRow() = %d, %v", n, err)
+	}
+}
+
+func TestLazyDatabaseCloseStopsRetries(t *testing.T) {
+	boom := errors.New("sin conexión")
+	attempts := make(chan struct{}, 1)
+	l := newLazy(func() (Database, error) {
+		select {
+		case attempts <- struct{}{}:
+		default:
+		}
+		return nil, boom
+	})
+	<-attempts
+
+	closed := make(chan struct{})
+	go func() {
+		l.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close no detuvo los reintentos")
+	}
+}
+
+func TestOpenLazyUnknownType(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		if db, err := Open(config.DatabaseConfig{Typo: "oracle", Lazy: lazy}); err == nil || db != nil {
+			t.Errorf("Open(lazy=%v) = %v, %v; se esperaba un error por el tipo desconocido", lazy, db, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLockTimeout indica que no se obtuvo el bloqueo dentro del tiempo de espera
+var ErrLockTimeout = errors.New("bloqueos: se agotó el tiempo de espera del bloqueo")
+
+// Intervalos de consulta de los bloqueos que no admiten espera en el servidor
+const (
+	lockMinPoll = 50 * time.Millisecond
+	lockMaxPoll = time.Second
+)
+
+// Locker es la capacidad de obtener bloqueos con nombre compartidos entre procesos, por ejemplo para que
+// una tarea programada se ejecute en una sola instancia del servicio. La implementan Postgres (advisory
+// locks) y MSSQL (sp_getapplock):
+//
+//	locker, ok := database.GetDatabase().(database.Locker)
+//	...
+//	lock, ok, err := locker.TryLock(ctx, "cierre-diario")
+//	if err != nil || !ok {
+//		return err
+//	}
+//	defer lock.Release(ctx)
+//
+// Sobre la conexión los bloqueos son de sesión: se mantienen en una conexión dedicada hasta Release y el
+// servidor los libera si la conexión se pierde. Sobre una transacción son de transacción y se liberan al
+// confirmarla o revertirla
+type Locker interface {
+	// TryLock intenta obtener el bloqueo sin esperar; devuelve false si lo tiene otro proceso
+	TryLock(ctx context.Context, name string) (*Lock, bool, error)
+	// Lock espera hasta obtener el bloqueo; devuelve ErrLockTimeout si no lo obtiene en `timeout`. Con
+	// `timeout` 0 espera hasta que termine `ctx`
+	Lock(ctx context.Context, name string, timeout time.Duration) (*Lock, error)
+}
+
+// Lock es un bloqueo obtenido con Locker
+type Lock struct {
+	name    string
+	release func(ctx context.Context) error
+	once    sync.Once
+	err     error
+}
+
+// Name devuelve el nombre del bloqueo
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// Release libera el bloqueo; las siguientes invocaciones no tienen efecto. En los bloqueos de transacción
+// no hace nada: se liberan al terminar la transacción
+func (l *Lock) Release(ctx context.Context) error {
+	l.once.Do(func() {
+		if l.release != nil {
+			l.err = l.release(ctx)
+		}
+	})
+	return l.err
+}
+
+// validateLockName valida el nombre de un bloqueo
+func validateLockName(name string, maxLen int) error {
+	if name == "" || len(name) > maxLen {
+		return errors.New("bloqueos: el nombre del bloqueo es obligatorio y no puede exceder el largo máximo del motor")
+	}
+	return nil
+}
+
+// pollLock reintenta `try` con espera creciente hasta obtener el bloqueo, agotar `timeout` o terminar `ctx`
+func pollLock(ctx context.Context, timeout time.Duration, try func(ctx context.Context) (bool, error)) error {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	wait := lockMinPoll
+	for {
+		ok, err := try(ctx)
+		if err != nil || ok {
+			return err
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-deadline:
+			t.Stop()
+			return ErrLockTimeout
+		case <-t.C:
+		}
+		wait = min(wait*2, lockMaxPoll)
+	}
+}
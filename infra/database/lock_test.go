@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPollLock(t *testing.T) {
+	tries := 0
+	err := pollLock(context.Background(), time.Second, func(context.Context) (bool, error) {
+		tries++
+		return tries == 3, nil
+	})
+	if err != nil || tries != 3 {
+		t.Fatalf("pollLock() = %v tras %d intentos; se esperaba obtenerlo al tercero", err, tries)
+	}
+}
+
+func TestPollLockTimeout(t *testing.T) {
+	err := pollLock(context.Background(), 10*time.Millisecond, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("pollLock() = %v; se esperaba ErrLockTimeout", err)
+	}
+}
+
+func TestPollLockContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := pollLock(ctx, 0, func(context.Context) (bool, error) {
+		cancel()
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("pollLock() = %v; se esperaba context.Canceled", err)
+	}
+
+	boom := errors.New("falla")
+	if err := pollLock(context.Background(), 0, func(context.Context) (bool, error) { return false, boom }); err != boom {
+		t.Fatalf("pollLock() = %v; se esperaba el error del intento", err)
+	}
+}
+
+func TestLockReleaseOnce(t *testing.T) {
+	calls := 0
+	l := &Lock{name: "cierre", release: func(context.Context) error {
+		calls++
+		return nil
+	}}
+
+	l.Release(context.Background())
+	l.Release(context.Background())
+	if calls != 1 {
+		t.Fatalf("release invocado %d veces", calls)
+	}
+
+	// Los bloqueos de transacción no tienen liberación propia
+	if err := (&Lock{name: "tx"}).Release(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockNamesAndTimeouts(t *testing.T) {
+	if err := validateLockName("", pgLockNameMax); err == nil {
+		t.Error("se esperaba un error con un nombre vacío")
+	}
+	if err := validateLockName(strings.Repeat("x", 256), mssqlLockNameMax); err == nil {
+		t.Error("se esperaba un error con un nombre muy largo para SQL Server")
+	}
+
+	for timeout, want := range map[time.Duration]int64{0: -1, -time.Second: -1, time.Microsecond: 1, 2 * time.Second: 2000} {
+		if got := mssqlLockTimeout(timeout); got != want {
+			t.Errorf("mssqlLockTimeout(%v) = %d, se esperaba %d", timeout, got, want)
+		}
+	}
+}
@@ -1,15 +1,29 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
-	_ "github.com/microsoft/go-mssqldb"
+	mssql "github.com/microsoft/go-mssqldb"
 	"github.com/wfrscltech/vulcano/config"
 )
 
 // Adaptador de Microsoft SQL Server siguiendo la especificación de la base de datos y la liberia microsoft/go-mssqldb
 type MSSQL struct {
 	*sqlBase
+
+	// Tabla de avisos usada por Listen/Notify (por defecto DefaultNotifyTable). Se debe configurar al
+	// iniciar el servicio, antes de usar las notificaciones
+	NotifyTable string
+	// Frecuencia de consulta de la tabla de avisos (por defecto DefaultPollInterval)
+	PollInterval time.Duration
+}
+
+// Representación de una transacción en Microsoft SQL Server
+type MSSQLTx struct {
+	*sqlBaseTx
 }
 
 func newMSSQLCnx(dcfg config.DatabaseConfig) (Database, error) {
@@ -21,6 +35,79 @@ func newMSSQLCnx(dcfg config.DatabaseConfig) (Database, error) {
 	return &MSSQL{sqlBase: &sqlBase{DB: cnx}}, nil
 }
 
+func (db *MSSQL) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MSSQLTx{&sqlBaseTx{tx}}, nil
+}
+
+// BulkInsert carga las filas con la API de bulk copy de SQL Server dentro de una transacción
+func (db *MSSQL) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	defer closeSource(src)
+	if err := validateBulkTarget(table, columns); err != nil {
+		return 0, err
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	n, err := bulkCopy(ctx, tx, table, columns, src, newBulkOptions(opts))
+	if err != nil {
+		return 0, err
+	}
+
+	return n, tx.Commit()
+}
+
+func (tx *MSSQLTx) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	defer closeSource(src)
+	if err := validateBulkTarget(table, columns); err != nil {
+		return 0, err
+	}
+	return bulkCopy(ctx, tx.Tx, table, columns, src, newBulkOptions(opts))
+}
+
+// bulkCopy envía las filas al servidor con el protocolo de bulk copy; el driver las transmite a medida
+// que se ejecuta cada fila y la sentencia final sin argumentos confirma el envío
+func bulkCopy(ctx context.Context, tx *sql.Tx, table string, columns []string, src RowSource, opts bulkOptions) (int64, error) {
+	stmt, err := tx.PrepareContext(ctx, mssql.CopyIn(table, mssql.BulkOptions{RowsPerBatch: opts.batchSize}, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	ps := &progressSource{RowSource: src, opts: opts}
+	for ps.Next() {
+		values, err := ps.Values()
+		if err != nil {
+			return 0, err
+		}
+		if len(values) != len(columns) {
+			return 0, fmt.Errorf("carga masiva: se esperaban %d valores por fila, se recibieron %d", len(columns), len(values))
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return 0, err
+		}
+	}
+	if err := ps.Err(); err != nil {
+		return 0, err
+	}
+
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+
+	ps.done()
+	return n, nil
+}
+
 func mssqldsn(dcfg config.DatabaseConfig) string {
 	return fmt.Sprintf(
 		"sqlserver://%s:%s@%s:%d?database=%s&encrypt=disable",
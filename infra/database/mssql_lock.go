@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// sp_getapplock admite recursos de hasta 255 caracteres
+const mssqlLockNameMax = 255
+
+// mssqlGetAppLock obtiene el bloqueo exclusivo y devuelve el código de sp_getapplock: 0 o 1 si se
+// obtuvo, -1 si se agotó la espera, -2 si se canceló, -3 si fue elegido víctima de un interbloqueo
+const mssqlGetAppLock = `DECLARE @r INT;
+EXEC @r = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = @p2, @LockTimeout = @p3;
+SELECT @r;`
+
+const mssqlReleaseAppLock = "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'"
+
+// Dueños de los bloqueos en sp_getapplock
+const (
+	mssqlOwnerSession     = "Session"
+	mssqlOwnerTransaction = "Transaction"
+)
+
+// rowQueryer es la parte común de *sql.Conn y *sql.Tx usada para obtener bloqueos
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// TryLock intenta obtener un bloqueo de sesión con sp_getapplock en una conexión dedicada del pool
+func (db *MSSQL) TryLock(ctx context.Context, name string) (*Lock, bool, error) {
+	lock, err := db.sessionLock(ctx, name, 0)
+	if err == ErrLockTimeout {
+		return nil, false, nil
+	}
+	return lock, err == nil, err
+}
+
+// Lock espera un bloqueo de sesión; la espera la controla el servidor con @LockTimeout
+func (db *MSSQL) Lock(ctx context.Context, name string, timeout time.Duration) (*Lock, error) {
+	return db.sessionLock(ctx, name, mssqlLockTimeout(timeout))
+}
+
+func (db *MSSQL) sessionLock(ctx context.Context, name string, timeoutMs int64) (*Lock, error) {
+	if err := validateLockName(name, mssqlLockNameMax); err != nil {
+		return nil, err
+	}
+
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mssqlAppLock(ctx, conn, name, mssqlOwnerSession, timeoutMs); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Si no se puede liberar, la conexión se descarta para que el servidor libere el bloqueo
+	return &Lock{name: name, release: func(ctx context.Context) error {
+		if _, err := conn.ExecContext(ctx, mssqlReleaseAppLock, name); err != nil {
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+			conn.Close()
+			return err
+		}
+		return conn.Close()
+	}}, nil
+}
+
+// TryLock intenta obtener un bloqueo de transacción con sp_getapplock
+func (tx *MSSQLTx) TryLock(ctx context.Context, name string) (*Lock, bool, error) {
+	lock, err := tx.appLock(ctx, name, 0)
+	if err == ErrLockTimeout {
+		return nil, false, nil
+	}
+	return lock, err == nil, err
+}
+
+// Lock espera un bloqueo de transacción; se libera al confirmar o revertir la transacción
+func (tx *MSSQLTx) Lock(ctx context.Context, name string, timeout time.Duration) (*Lock, error) {
+	return tx.appLock(ctx, name, mssqlLockTimeout(timeout))
+}
+
+func (tx *MSSQLTx) appLock(ctx context.Context, name string, timeoutMs int64) (*Lock, error) {
+	if err := validateLockName(name, mssqlLockNameMax); err != nil {
+		return nil, err
+	}
+
+	if err := mssqlAppLock(ctx, tx.Tx, name, mssqlOwnerTransaction, timeoutMs); err != nil {
+		return nil, err
+	}
+	return &Lock{name: name}, nil
+}
+
+// mssqlLockTimeout convierte la espera al valor de @LockTimeout: -1 espera indefinidamente
+func mssqlLockTimeout(timeout time.Duration) int64 {
+	if timeout <= 0 {
+		return -1
+	}
+	return max(timeout.Milliseconds(), 1)
+}
+
+func mssqlAppLock(ctx context.Context, q rowQueryer, name, owner string, timeoutMs int64) error {
+	var code int
+	if err := q.QueryRowContext(ctx, mssqlGetAppLock, name, owner, timeoutMs).Scan(&code); err != nil {
+		return err
+	}
+
+	switch code {
+	case 0, 1:
+		return nil
+	case -1:
+		return ErrLockTimeout
+	default:
+		return fmt.Errorf("bloqueos: sp_getapplock devolvió %d para `%s`", code, name)
+	}
+}
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Valores por defecto de las notificaciones de SQL Server
+const (
+	DefaultNotifyTable  = "vulcano_notifications"
+	DefaultPollInterval = 2 * time.Second
+)
+
+// MSSQLNotificationsDDL crea la tabla de avisos usada por las notificaciones de SQL Server. La columna
+// `version` permite leer los avisos sin saltos aunque las transacciones se confirmen fuera de orden
+const MSSQLNotificationsDDL = `CREATE TABLE vulcano_notifications (
+	id BIGINT IDENTITY(1,1) PRIMARY KEY,
+	channel NVARCHAR(63) NOT NULL,
+	payload NVARCHAR(MAX) NOT NULL,
+	created_at DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME(),
+	version ROWVERSION NOT NULL
+);
+CREATE INDEX ix_vulcano_notifications_version ON vulcano_notifications (version);`
+
+// Listen se suscribe a los canales consultando periódicamente la tabla de avisos (ver
+// MSSQLNotificationsDDL), ya que SQL Server no ofrece un equivalente a LISTEN/NOTIFY. Solo se entregan
+// los avisos publicados después de la suscripción
+func (db *MSSQL) Listen(ctx context.Context, channels ...string) (<-chan Notification, error) {
+	if err := validateChannels(channels); err != nil {
+		return nil, err
+	}
+	table, err := db.notifyTable()
+	if err != nil {
+		return nil, err
+	}
+
+	// Los avisos con versión menor a MIN_ACTIVE_ROWVERSION ya están confirmados; los que están en curso
+	// tendrán una versión mayor, por lo que no se pierden
+	var last []byte
+	err = db.DB.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(version), 0x0000000000000000) FROM "+table+" WHERE version < MIN_ACTIVE_ROWVERSION()",
+	).Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+
+	marks := make([]string, len(channels))
+	args := make([]any, 0, len(channels)+1)
+	args = append(args, last)
+	for i, ch := range channels {
+		marks[i] = Placeholder("mssql", i+2)
+		args = append(args, ch)
+	}
+	query := fmt.Sprintf(
+		"SELECT version, channel, payload FROM %s WHERE version > @p1 AND version < MIN_ACTIVE_ROWVERSION() AND channel IN (%s) ORDER BY version",
+		table, strings.Join(marks, ", "),
+	)
+
+	out := make(chan Notification, notifyBuffer)
+	go func() {
+		defer close(out)
+
+		interval := db.pollInterval()
+		wait := retryMinBackoff
+		for {
+			t := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			if err := db.poll(ctx, query, args, out); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				logNotifyError("Error al consultar las notificaciones de SQL Server", err, wait)
+				var ok bool
+				if wait, ok = backoff(ctx, wait); !ok {
+					return
+				}
+				continue
+			}
+			wait = retryMinBackoff
+		}
+	}()
+
+	return out, nil
+}
+
+// poll entrega los avisos nuevos y actualiza la última versión leída en `args[0]`
+func (db *MSSQL) poll(ctx context.Context, query string, args []any, out chan<- Notification) error {
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version []byte
+		var n Notification
+		if err := rows.Scan(&version, &n.Channel, &n.Payload); err != nil {
+			return err
+		}
+		if !deliver(ctx, out, n) {
+			return ctx.Err()
+		}
+		args[0] = version
+	}
+
+	return rows.Err()
+}
+
+// Notify publica el aviso insertándolo en la tabla de avisos
+func (db *MSSQL) Notify(ctx context.Context, channel, payload string) error {
+	if err := validateChannels([]string{channel}); err != nil {
+		return err
+	}
+	table, err := db.notifyTable()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.DB.ExecContext(ctx, "INSERT INTO "+table+" (channel, payload) VALUES (@p1, @p2)", channel, payload)
+	return err
+}
+
+// PurgeNotifications elimina los avisos publicados hace más de `olderThan`; devuelve la cantidad eliminada
+func (db *MSSQL) PurgeNotifications(ctx context.Context, olderThan time.Duration) (int64, error) {
+	table, err := db.notifyTable()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := db.DB.ExecContext(ctx,
+		"DELETE FROM "+table+" WHERE created_at < DATEADD(SECOND, -@p1, SYSUTCDATETIME())",
+		int64(olderThan.Seconds()),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (db *MSSQL) notifyTable() (string, error) {
+	if db.NotifyTable == "" {
+		return DefaultNotifyTable, nil
+	}
+	if !identifierRe.MatchString(db.NotifyTable) {
+		return "", fmt.Errorf("notificaciones: el nombre de tabla `%s` no es válido", db.NotifyTable)
+	}
+	return db.NotifyTable, nil
+}
+
+func (db *MSSQL) pollInterval() time.Duration {
+	if db.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return db.PollInterval
+}
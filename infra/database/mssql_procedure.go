@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// queryer es la parte común de *sql.DB y *sql.Tx usada para ejecutar procedimientos
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// CallProcedure ejecuta el procedimiento almacenado mediante una llamada RPC. Los parámetros deben tener
+// nombre; los de salida se envían como `sql.Out`
+func (db *MSSQL) CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error) {
+	return callMSSQLProcedure(ctx, db.DB, name, params)
+}
+
+func (tx *MSSQLTx) CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error) {
+	return callMSSQLProcedure(ctx, tx.Tx, name, params)
+}
+
+func callMSSQLProcedure(ctx context.Context, q queryer, name string, params []Param) (*Results, error) {
+	if err := validateProcedure(name, params, true); err != nil {
+		return nil, err
+	}
+
+	res := &Results{}
+	args := make([]any, 0, len(params)+1)
+	for _, p := range params {
+		switch p.Dir {
+		case DirOut:
+			args = append(args, sql.Named(p.Name, sql.Out{Dest: p.Dest}))
+		case DirInOut:
+			args = append(args, sql.Named(p.Name, sql.Out{Dest: p.Dest, In: true}))
+		default:
+			args = append(args, sql.Named(p.Name, p.Value))
+		}
+	}
+	args = append(args, (*mssql.ReturnStatus)(&res.code))
+
+	// El driver ejecuta como RPC las consultas formadas solo por el nombre del procedimiento
+	rows, err := q.QueryContext(ctx, name, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.rows = &sqlBaseRows{rows}
+	res.next = rows.NextResultSet
+	return res, nil
+}
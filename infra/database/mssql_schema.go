@@ -0,0 +1,58 @@
+package database
+
+import "context"
+
+// Consultas de catálogo de SQL Server. Se agrupan las columnas en Go para mantener la compatibilidad con
+// versiones sin STRING_AGG
+var mssqlSchemaQueries = schemaQueries{
+	tables: `SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE
+FROM INFORMATION_SCHEMA.TABLES
+WHERE (@p1 = '' OR TABLE_SCHEMA = @p1)
+ORDER BY TABLE_SCHEMA, TABLE_NAME`,
+
+	table: `SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE
+FROM INFORMATION_SCHEMA.TABLES
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND TABLE_NAME = @p2`,
+
+	columns: `SELECT c.name, UPPER(ty.name), c.is_nullable, dc.definition,
+  CASE
+    WHEN ty.name IN ('nchar', 'nvarchar') AND c.max_length > 0 THEN c.max_length / 2
+    WHEN ty.name IN ('char', 'varchar', 'nchar', 'nvarchar', 'binary', 'varbinary') THEN c.max_length
+  END,
+  CASE WHEN ty.name IN ('decimal', 'numeric') THEN CAST(c.precision AS INT) END,
+  CASE WHEN ty.name IN ('decimal', 'numeric') THEN CAST(c.scale AS INT) END,
+  c.column_id, c.is_identity
+FROM sys.columns c
+JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+WHERE c.object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2))
+ORDER BY c.column_id`,
+
+	indexes: `SELECT i.name, i.is_unique, i.is_primary_key, c.name
+FROM sys.indexes i
+JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id AND ic.is_included_column = 0
+JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE i.object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2)) AND i.name IS NOT NULL
+ORDER BY i.name, ic.key_ordinal`,
+
+	foreignKeys: `SELECT fk.name, rs.name, rt.name, pc.name, rc.name,
+  fk.delete_referential_action_desc, fk.update_referential_action_desc
+FROM sys.foreign_keys fk
+JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+JOIN sys.objects rt ON rt.object_id = fkc.referenced_object_id
+JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+WHERE fk.parent_object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2))
+ORDER BY fk.name, fkc.constraint_column_id`,
+}
+
+// Tables lista las tablas y vistas consultando INFORMATION_SCHEMA
+func (db *MSSQL) Tables(ctx context.Context, schema string) ([]TableRef, error) {
+	return listTables(ctx, db, mssqlSchemaQueries, schema)
+}
+
+// DescribeTable describe la tabla consultando las vistas sys.*
+func (db *MSSQL) DescribeTable(ctx context.Context, schema, table string) (*Table, error) {
+	return describeTable(ctx, db, mssqlSchemaQueries, schema, table)
+}
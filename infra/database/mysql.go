@@ -0,0 +1,87 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// Adaptador de MySQL/MariaDB siguiendo la especificación de la base de datos y la liberia go-sql-driver/mysql
+type MySQL struct {
+	*sqlBase
+}
+
+func newMySQLCnx(dcfg config.DatabaseConfig) (Database, error) {
+	dsn, err := mysqldsn(dcfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cnx, err := newConnection("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// El servidor cierra las conexiones inactivas (wait_timeout), por lo que se renuevan antes de que
+	// eso ocurra, tal como recomienda el driver
+	cnx.SetConnMaxLifetime(3 * time.Minute)
+	cnx.SetMaxOpenConns(10)
+	cnx.SetMaxIdleConns(10)
+
+	return &MySQL{sqlBase: &sqlBase{DB: cnx}}, nil
+}
+
+func mysqldsn(dcfg config.DatabaseConfig) (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = dcfg.User
+	cfg.Passwd = dcfg.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(dcfg.Host, strconv.Itoa(dcfg.Port))
+	cfg.DBName = dcfg.Name
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+
+	switch dcfg.TLS {
+	case "", config.TLSModeDisable:
+		cfg.TLSConfig = "false"
+	case config.TLSModeSkipVerify, config.TLSModePreferred:
+		cfg.TLSConfig = dcfg.TLS
+	case config.TLSModeRequire:
+		cfg.TLSConfig = "true"
+	default:
+		return "", fmt.Errorf("modo TLS `%s` no soportado", dcfg.TLS)
+	}
+
+	// Con un certificado de autoridad propio se registra una configuración TLS con nombre para el driver
+	if dcfg.TLSCAFile != "" && dcfg.TLS != "" && dcfg.TLS != config.TLSModeDisable {
+		pem, err := os.ReadFile(dcfg.TLSCAFile)
+		if err != nil {
+			return "", fmt.Errorf("error al leer el certificado de autoridad `%s`: %w", dcfg.TLSCAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("el archivo `%s` no contiene certificados PEM válidos", dcfg.TLSCAFile)
+		}
+
+		name := "vulcano-" + cfg.Addr
+		err = mysql.RegisterTLSConfig(name, &tls.Config{
+			RootCAs:            pool,
+			ServerName:         dcfg.Host,
+			InsecureSkipVerify: dcfg.TLS == config.TLSModeSkipVerify,
+		})
+		if err != nil {
+			return "", err
+		}
+		cfg.TLSConfig = name
+	}
+
+	return cfg.FormatDSN(), nil
+}
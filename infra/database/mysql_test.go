@@ -0,0 +1,56 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// TestMySQLDSN valida la construcción del DSN con opciones TLS
+func TestMySQLDSN(t *testing.T) {
+	base := config.DatabaseConfig{
+		Host:     "db.local",
+		Port:     3306,
+		User:     "app",
+		Password: "secret",
+		Name:     "erp",
+		Typo:     config.DatabaseTypeMysql,
+	}
+
+	tests := []struct {
+		name     string
+		tls      string
+		contains string
+		fails    bool
+	}{
+		{name: "Sin TLS", tls: "", contains: "tls=false"},
+		{name: "TLS obligatorio", tls: config.TLSModeRequire, contains: "tls=true"},
+		{name: "TLS sin verificación", tls: config.TLSModeSkipVerify, contains: "tls=skip-verify"},
+		{name: "TLS preferido", tls: config.TLSModePreferred, contains: "tls=preferred"},
+		{name: "Modo inválido", tls: "always", fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dcfg := base
+			dcfg.TLS = tt.tls
+			dsn, err := mysqldsn(dcfg)
+			if tt.fails {
+				if err == nil {
+					t.Error("Se esperaba un error pero no se obtuvo ninguno")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+			}
+			if !strings.HasPrefix(dsn, "app:secret@tcp(db.local:3306)/erp?") {
+				t.Errorf("DSN inesperado: %q", dsn)
+			}
+			if !strings.Contains(dsn, tt.contains) || !strings.Contains(dsn, "parseTime=true") {
+				t.Errorf("Se esperaba que el DSN contenga %q y parseTime=true, obtuvo: %q", tt.contains, dsn)
+			}
+		})
+	}
+}
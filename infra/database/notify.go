@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Tamaño del buffer de los canales de notificaciones
+const notifyBuffer = 64
+
+// Límites del reintento de conexión de los suscriptores y de la conexión diferida
+const (
+	retryMinBackoff = time.Second
+	retryMaxBackoff = 30 * time.Second
+)
+
+// Notification representa un aviso recibido en un canal
+type Notification struct {
+	// Canal en el que se publicó el aviso
+	Channel string
+	// Contenido del aviso
+	Payload string
+}
+
+// Notifier es la capacidad de publicar y recibir avisos entre procesos a través de la base de datos, útil
+// para invalidar cachés o refrescar interfaces cuando cambian los datos. La implementan Postgres (con
+// LISTEN/NOTIFY) y MSSQL (consultando periódicamente una tabla de avisos):
+//
+//	if n, ok := database.GetDatabase().(database.Notifier); ok {
+//		avisos, err := n.Listen(ctx, "clientes")
+//		...
+//		for a := range avisos {
+//			cache.Invalidar(a.Payload)
+//		}
+//	}
+type Notifier interface {
+	// Listen se suscribe a los canales indicados. Los avisos se entregan en el canal devuelto, que se
+	// cierra cuando termina `ctx`. Si se pierde la conexión se reintenta en segundo plano; los avisos
+	// publicados mientras tanto se pierden
+	Listen(ctx context.Context, channels ...string) (<-chan Notification, error)
+	// Notify publica un aviso en el canal indicado
+	Notify(ctx context.Context, channel, payload string) error
+}
+
+// validateChannels valida los nombres de los canales de una suscripción
+func validateChannels(channels []string) error {
+	if len(channels) == 0 {
+		return errors.New("notificaciones: se requiere al menos un canal")
+	}
+	for _, ch := range channels {
+		if ch == "" || len(ch) > 63 {
+			return errors.New("notificaciones: el nombre del canal debe tener entre 1 y 63 caracteres")
+		}
+	}
+	return nil
+}
+
+// backoff espera el tiempo indicado o hasta que termine el contexto, y devuelve el siguiente intervalo
+func backoff(ctx context.Context, wait time.Duration) (time.Duration, bool) {
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return wait, false
+	case <-t.C:
+	}
+
+	return min(wait*2, retryMaxBackoff), true
+}
+
+// deliver envía el aviso al suscriptor, salvo que termine el contexto
+func deliver(ctx context.Context, out chan<- Notification, n Notification) bool {
+	select {
+	case out <- n:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func logNotifyError(msg string, err error, wait time.Duration) {
+	slog.Warn(msg, slog.String("error", err.Error()), slog.String("retry_in", wait.String()))
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateChannels(t *testing.T) {
+	if err := validateChannels([]string{"clientes", "pedidos"}); err != nil {
+		t.Fatalf("se esperaban canales válidos: %v", err)
+	}
+
+	for name, channels := range map[string][]string{
+		"sin canales": nil,
+		"vacío":       {""},
+		"muy largo":   {strings.Repeat("c", 64)},
+	} {
+		if err := validateChannels(channels); err == nil {
+			t.Errorf("%s: se esperaba un error", name)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	next, ok := backoff(context.Background(), time.Millisecond)
+	if !ok || next != 2*time.Millisecond {
+		t.Fatalf("backoff = %v, %v; se esperaba 2ms, true", next, ok)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := backoff(ctx, time.Hour); ok {
+		t.Fatal("se esperaba que el contexto cancelado interrumpa la espera")
+	}
+}
+
+func TestMSSQLNotifyTable(t *testing.T) {
+	db := &MSSQL{}
+	if table, _ := db.notifyTable(); table != DefaultNotifyTable {
+		t.Fatalf("tabla por defecto = %q", table)
+	}
+	if db.pollInterval() != DefaultPollInterval {
+		t.Fatalf("intervalo por defecto = %v", db.pollInterval())
+	}
+
+	db.NotifyTable = "avisos; DROP TABLE x"
+	if _, err := db.notifyTable(); err == nil {
+		t.Fatal("se esperaba un error con un nombre de tabla inválido")
+	}
+}
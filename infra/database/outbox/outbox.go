@@ -0,0 +1,154 @@
+// Package outbox implementa el patrón de bandeja de salida transaccional: los eventos se guardan en una
+// tabla dentro de la misma transacción que la escritura de negocio, y un Relay los entrega después a los
+// publicadores (webhooks, bus en proceso). Si el proceso termina entre la escritura y la publicación, el
+// evento queda pendiente y se entrega al reiniciar, en lugar de perderse.
+//
+// La entrega es "al menos una vez": los consumidores deben descartar los duplicados usando Event.ID.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Nombre por defecto de la tabla de eventos
+const DefaultTable = "vulcano_outbox"
+
+// PostgresDDL crea la tabla de eventos en PostgreSQL
+const PostgresDDL = `CREATE TABLE IF NOT EXISTS vulcano_outbox (
+	id BIGSERIAL PRIMARY KEY,
+	topic VARCHAR(255) NOT NULL,
+	payload TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMPTZ NOT NULL,
+	delivered_at TIMESTAMPTZ NULL,
+	last_error TEXT NULL
+);
+CREATE INDEX IF NOT EXISTS ix_vulcano_outbox_pending ON vulcano_outbox (next_attempt_at) WHERE delivered_at IS NULL;`
+
+// MSSQLDDL crea la tabla de eventos en SQL Server
+const MSSQLDDL = `CREATE TABLE vulcano_outbox (
+	id BIGINT IDENTITY(1,1) PRIMARY KEY,
+	topic NVARCHAR(255) NOT NULL,
+	payload NVARCHAR(MAX) NOT NULL,
+	created_at DATETIME2 NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at DATETIME2 NOT NULL,
+	delivered_at DATETIME2 NULL,
+	last_error NVARCHAR(MAX) NULL
+);
+CREATE INDEX ix_vulcano_outbox_pending ON vulcano_outbox (next_attempt_at) WHERE delivered_at IS NULL;`
+
+// SQLiteDDL crea la tabla de eventos en SQLite, pensada para desarrollo y pruebas
+const SQLiteDDL = `CREATE TABLE IF NOT EXISTS vulcano_outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMP NOT NULL,
+	delivered_at TIMESTAMP NULL,
+	last_error TEXT NULL
+)`
+
+// ErrNoTx indica que se intentó publicar un evento fuera de una transacción
+var ErrNoTx = errors.New("outbox: el evento se debe publicar dentro de una transacción")
+
+// tableRe valida el nombre de la tabla, que se interpola en las consultas
+var tableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Event es un evento de la bandeja de salida
+type Event struct {
+	// Identificador único, útil para descartar entregas duplicadas
+	ID int64
+	// Tema del evento (ej. `pedido.creado`)
+	Topic string
+	// Contenido JSON del evento
+	Payload json.RawMessage
+	// Momento en que se registró el evento
+	CreatedAt time.Time
+	// Intentos de entrega previos
+	Attempts int
+}
+
+// Outbox registra los eventos en la tabla de eventos del motor indicado
+type Outbox struct {
+	typo  string
+	table string
+}
+
+// Option configura la bandeja de salida
+type Option func(*Outbox)
+
+// WithTable cambia la tabla de eventos (por defecto DefaultTable)
+func WithTable(table string) Option {
+	return func(o *Outbox) {
+		o.table = table
+	}
+}
+
+// New crea la bandeja de salida para el tipo de base de datos indicado (config.DatabaseConfig.Typo)
+func New(typo string, opts ...Option) (*Outbox, error) {
+	o := &Outbox{typo: typo, table: DefaultTable}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	switch typo {
+	case config.DatabaseTypePostgres, config.DatabaseTypeMssql, config.DatabaseTypeSqlite:
+	default:
+		return nil, fmt.Errorf("outbox: el tipo de base de datos `%s` no está soportado", typo)
+	}
+	if !tableRe.MatchString(o.table) {
+		return nil, fmt.Errorf("outbox: el nombre de tabla `%s` no es válido", o.table)
+	}
+	return o, nil
+}
+
+// Publish registra un evento en la transacción activa del contexto (ver database.RunInTx y
+// TransactionMiddleware), de modo que se confirma o se revierte junto con la escritura de negocio.
+// `payload` se serializa a JSON, salvo que ya sea json.RawMessage o []byte
+func (o *Outbox) Publish(ctx context.Context, topic string, payload any) error {
+	tx, ok := database.TxFrom(ctx)
+	if !ok {
+		return ErrNoTx
+	}
+	if topic == "" {
+		return errors.New("outbox: el tema del evento es obligatorio")
+	}
+
+	var data []byte
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	default:
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("outbox: no se pudo serializar el evento: %w", err)
+		}
+	}
+	if !json.Valid(data) {
+		return errors.New("outbox: el contenido del evento no es JSON válido")
+	}
+
+	now := time.Now().UTC()
+	query := database.Rebind(o.typo, "INSERT INTO "+o.table+" (topic, payload, created_at, next_attempt_at) VALUES (?, ?, ?, ?)")
+	_, err := tx.Exec(ctx, query, topic, string(data), now, now)
+	return err
+}
+
+// Purge elimina los eventos entregados antes de `before` y devuelve la cantidad eliminada
+func (o *Outbox) Purge(ctx context.Context, q database.Querier, before time.Time) (int64, error) {
+	query := database.Rebind(o.typo, "DELETE FROM "+o.table+" WHERE delivered_at IS NOT NULL AND delivered_at < ?")
+	return q.Exec(ctx, query, before.UTC())
+}
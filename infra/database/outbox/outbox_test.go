@@ -0,0 +1,249 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+type pedido struct {
+	ID    int     `json:"id"`
+	Total float64 `json:"total"`
+}
+
+// outboxDB crea una base SQLite en memoria con la tabla de eventos y la deja en el contexto
+func outboxDB(t *testing.T) (context.Context, database.Database, *Outbox) {
+	t.Helper()
+
+	db, err := database.Open(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	ctx := database.WithDatabase(context.Background(), db)
+	if _, err := db.Exec(ctx, SQLiteDDL); err != nil {
+		t.Fatalf("Error al crear la tabla de eventos: %v", err)
+	}
+
+	o, err := New(config.DatabaseTypeSqlite)
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	return ctx, db, o
+}
+
+func TestPublish(t *testing.T) {
+	ctx, db, o := outboxDB(t)
+
+	if err := o.Publish(ctx, "pedido.creado", pedido{ID: 1}); !errors.Is(err, ErrNoTx) {
+		t.Errorf("Publish() fuera de una transacción = %v; se esperaba ErrNoTx", err)
+	}
+
+	err := database.RunInTx(ctx, func(ctx context.Context) error {
+		return o.Publish(ctx, "pedido.creado", pedido{ID: 1, Total: 150})
+	})
+	if err != nil {
+		t.Fatalf("RunInTx() = %v", err)
+	}
+
+	// El evento de una transacción revertida no se registra
+	_ = database.RunInTx(ctx, func(ctx context.Context) error {
+		if err := o.Publish(ctx, "pedido.creado", pedido{ID: 2}); err != nil {
+			return err
+		}
+		return errors.New("falla de negocio")
+	})
+
+	var n int
+	var payload string
+	if err := db.QueryRow(ctx, "SELECT COUNT(*), MAX(payload) FROM vulcano_outbox").Scan(&n, &payload); err != nil {
+		t.Fatalf("Error al consultar: %v", err)
+	}
+	if n != 1 || payload != `{"id":1,"total":150}` {
+		t.Errorf("Se esperaba un evento del pedido 1, obtuvo %d %s", n, payload)
+	}
+
+	if _, err := New(config.DatabaseTypeSqlite, WithTable("eventos; DROP")); err == nil {
+		t.Error("Se esperaba error con un nombre de tabla inválido")
+	}
+}
+
+func TestRelay(t *testing.T) {
+	ctx, db, o := outboxDB(t)
+
+	err := database.RunInTx(ctx, func(ctx context.Context) error {
+		if err := o.Publish(ctx, "pedido.creado", pedido{ID: 1}); err != nil {
+			return err
+		}
+		return o.Publish(ctx, "pedido.anulado", json.RawMessage(`{"id":2}`))
+	})
+	if err != nil {
+		t.Fatalf("RunInTx() = %v", err)
+	}
+
+	var mu sync.Mutex
+	var received []string
+	fallar := true
+	bus := NewBus()
+	bus.Subscribe("*", func(ctx context.Context, e Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if e.Topic == "pedido.anulado" && fallar {
+			fallar = false
+			return errors.New("destino no disponible")
+		}
+		received = append(received, e.Topic)
+		return nil
+	})
+
+	relay := NewRelay(db, o, bus, WithBackoff(time.Hour, time.Hour))
+	if n, err := relay.RelayOnce(ctx); err != nil || n != 2 {
+		t.Fatalf("RelayOnce() = %d, %v; se esperaban 2 eventos", n, err)
+	}
+
+	// El evento fallido espera su reintento
+	if n, err := relay.RelayOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("RelayOnce() = %d, %v; no se esperaban eventos", n, err)
+	}
+	var attempts int
+	var lastError string
+	if err := db.QueryRow(ctx, "SELECT attempts, last_error FROM vulcano_outbox WHERE topic = 'pedido.anulado'").Scan(&attempts, &lastError); err != nil {
+		t.Fatalf("Error al consultar: %v", err)
+	}
+	if attempts != 1 || lastError != "destino no disponible" {
+		t.Errorf("Se esperaba un intento fallido, obtuvo %d %q", attempts, lastError)
+	}
+
+	// Sin espera entre reintentos se entrega en el siguiente lote
+	relay = NewRelay(db, o, bus, WithBackoff(0, 0))
+	if _, err := db.Exec(ctx, "UPDATE vulcano_outbox SET next_attempt_at = ? WHERE delivered_at IS NULL", time.Now().UTC()); err != nil {
+		t.Fatalf("Error al adelantar el reintento: %v", err)
+	}
+	if n, err := relay.RelayOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RelayOnce() = %d, %v; se esperaba 1 evento", n, err)
+	}
+
+	if len(received) != 2 || received[0] != "pedido.creado" || received[1] != "pedido.anulado" {
+		t.Errorf("Eventos recibidos inesperados: %v", received)
+	}
+
+	if n, err := o.Purge(ctx, db, time.Now().Add(time.Minute)); err != nil || n != 2 {
+		t.Errorf("Purge() = %d, %v; se esperaban 2 eventos eliminados", n, err)
+	}
+}
+
+// TestRelayLease valida que el lote quede reservado sin una transacción abierta durante la entrega y que
+// los eventos no entregados se liberen al cancelar
+func TestRelayLease(t *testing.T) {
+	ctx, db, o := outboxDB(t)
+
+	err := database.RunInTx(ctx, func(ctx context.Context) error {
+		if err := o.Publish(ctx, "pedido.creado", pedido{ID: 1}); err != nil {
+			return err
+		}
+		return o.Publish(ctx, "pedido.creado", pedido{ID: 2})
+	})
+	if err != nil {
+		t.Fatalf("RunInTx() = %v", err)
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	other := NewRelay(db, o, PublisherFunc(func(context.Context, Event) error { return nil }))
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		// Otra instancia no toma los eventos reservados
+		if n, err := other.RelayOnce(ctx); err != nil || n != 0 {
+			t.Errorf("RelayOnce() de otra instancia = %d, %v; no se esperaban eventos", n, err)
+		}
+		if e.ID == 2 {
+			cancel()
+			return ctx.Err()
+		}
+		return nil
+	}))
+
+	if n, err := relay.RelayOnce(rctx); !errors.Is(err, context.Canceled) || n != 2 {
+		t.Fatalf("RelayOnce() = %d, %v; se esperaba la cancelación tras tomar 2 eventos", n, err)
+	}
+
+	// El evento entregado queda registrado y el cancelado vuelve a estar disponible
+	if n, err := other.RelayOnce(ctx); err != nil || n != 1 {
+		t.Errorf("RelayOnce() tras cancelar = %d, %v; se esperaba 1 evento", n, err)
+	}
+	var pending int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM vulcano_outbox WHERE delivered_at IS NULL").Scan(&pending); err != nil || pending != 0 {
+		t.Errorf("Se esperaban todos los eventos entregados, quedan %d (%v)", pending, err)
+	}
+
+	if err := NewRelay(db, o, other.pub, WithBatchSize(0)).Start(); err == nil {
+		t.Error("Se esperaba error con un lote de 0 eventos")
+	}
+}
+
+func TestRelayRunner(t *testing.T) {
+	ctx, db, o := outboxDB(t)
+
+	delivered := make(chan Event, 1)
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		delivered <- e
+		return nil
+	}), WithPollInterval(10*time.Millisecond))
+
+	go relay.Start()
+	defer relay.Shutdown(context.Background())
+
+	err := database.RunInTx(ctx, func(ctx context.Context) error {
+		return o.Publish(ctx, "pedido.creado", pedido{ID: 7})
+	})
+	if err != nil {
+		t.Fatalf("RunInTx() = %v", err)
+	}
+
+	select {
+	case e := <-delivered:
+		if string(e.Payload) != `{"id":7,"total":0}` || e.CreatedAt.IsZero() {
+			t.Errorf("Evento inesperado: %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("El relay no entregó el evento")
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := relay.Shutdown(sctx); err != nil {
+		t.Errorf("Shutdown() = %v", err)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var got http.Header
+	var status = http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	wh := &Webhook{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer secreto"}}
+	e := Event{ID: 42, Topic: "pedido.creado", Payload: json.RawMessage(`{}`)}
+	if err := wh.Publish(context.Background(), e); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	if got.Get("X-Event-ID") != "42" || got.Get("X-Event-Topic") != "pedido.creado" || got.Get("Authorization") != "Bearer secreto" {
+		t.Errorf("Encabezados inesperados: %v", got)
+	}
+
+	status = http.StatusBadGateway
+	if err := wh.Publish(context.Background(), e); err == nil {
+		t.Error("Se esperaba error con una respuesta 502")
+	}
+}
@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Publisher entrega los eventos a su destino. Un error deja el evento pendiente para reintentarlo
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// PublisherFunc adapta una función a Publisher
+type PublisherFunc func(ctx context.Context, e Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// Bus entrega los eventos a los manejadores suscritos en el mismo proceso
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]PublisherFunc
+}
+
+// NewBus crea un bus de eventos en proceso
+func NewBus() *Bus {
+	return &Bus{handlers: map[string][]PublisherFunc{}}
+}
+
+// Subscribe registra un manejador para los eventos del tema indicado; con `*` recibe todos los temas
+func (b *Bus) Subscribe(topic string, h PublisherFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], h)
+}
+
+// Publish entrega el evento a todos los manejadores de su tema. Si alguno falla el evento se reintenta
+// completo, por lo que los manejadores deben ser idempotentes
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	handlers := slices.Concat(b.handlers[e.Topic], b.handlers["*"])
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Webhook entrega los eventos con un POST del contenido JSON a una URL. El tema y el identificador del
+// evento se envían en los encabezados `X-Event-Topic` y `X-Event-ID`; cualquier respuesta que no sea 2xx
+// se considera un fallo
+type Webhook struct {
+	URL string
+	// Encabezados adicionales (ej. `Authorization`)
+	Headers map[string]string
+	// Cliente HTTP; por defecto uno con 10 segundos de tiempo máximo
+	Client *http.Client
+}
+
+var defaultWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+func (w *Webhook) Publish(ctx context.Context, e Event) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(e.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Topic", e.Topic)
+	req.Header.Set("X-Event-ID", strconv.FormatInt(e.ID, 10))
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = defaultWebhookClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("el webhook respondió %d", res.StatusCode)
+	}
+	return nil
+}
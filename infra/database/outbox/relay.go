@@ -0,0 +1,301 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Valores por defecto del Relay
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 100
+	DefaultMaxAttempts  = 20
+	DefaultMinBackoff   = 5 * time.Second
+	DefaultMaxBackoff   = time.Hour
+	DefaultLease        = 10 * time.Minute
+)
+
+// RelayOption configura el Relay
+type RelayOption func(*Relay)
+
+// WithPollInterval cambia el intervalo de consulta de la tabla cuando no hay eventos pendientes
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.pollInterval = d
+	}
+}
+
+// WithBatchSize cambia la cantidad máxima de eventos tomados por consulta
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		r.batchSize = n
+	}
+}
+
+// WithMaxAttempts cambia la cantidad de intentos tras la cual un evento deja de reintentarse. Los eventos
+// agotados quedan en la tabla sin `delivered_at` para su revisión
+func WithMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		r.maxAttempts = n
+	}
+}
+
+// WithLease cambia el plazo por el que un lote tomado queda reservado para este Relay. Debe cubrir la
+// entrega de todo el lote (p. ej. `WithBatchSize` por el timeout del Webhook); si vence antes, otra
+// instancia puede volver a entregar los eventos pendientes del lote
+func WithLease(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.lease = d
+	}
+}
+
+// WithBackoff cambia la espera entre reintentos, que se duplica en cada fallo desde `minWait` hasta `maxWait`
+func WithBackoff(minWait, maxWait time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.minBackoff, r.maxBackoff = minWait, maxWait
+	}
+}
+
+// Relay entrega los eventos pendientes de la bandeja de salida al publicador. Implementa service.Runner
+// para ejecutarse junto al servidor HTTP. Varias instancias pueden trabajar sobre la misma tabla: cada
+// lote se toma en una transacción corta (con `FOR UPDATE SKIP LOCKED` en PostgreSQL y `UPDLOCK, READPAST`
+// en SQL Server) que lo reserva durante el plazo de WithLease, de modo que un evento no se entrega dos
+// veces en paralelo. Las entregas se hacen fuera de esa transacción y cada resultado se registra por
+// separado
+type Relay struct {
+	db     database.Database
+	outbox *Outbox
+	pub    Publisher
+
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	lease        time.Duration
+
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewRelay crea el Relay que entrega los eventos de `o`, leídos desde `db`, al publicador `pub`
+func NewRelay(db database.Database, o *Outbox, pub Publisher, opts ...RelayOption) *Relay {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Relay{
+		db:           db,
+		outbox:       o,
+		pub:          pub,
+		pollInterval: DefaultPollInterval,
+		batchSize:    DefaultBatchSize,
+		maxAttempts:  DefaultMaxAttempts,
+		minBackoff:   DefaultMinBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+		lease:        DefaultLease,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start entrega los eventos pendientes hasta que se invoque Shutdown
+func (r *Relay) Start() error {
+	if !r.started.CompareAndSwap(false, true) {
+		return errors.New("outbox: el relay ya está iniciado")
+	}
+	defer close(r.done)
+	if r.batchSize < 1 {
+		return errors.New("outbox: el tamaño del lote debe ser mayor a 0")
+	}
+	if r.lease <= 0 {
+		return errors.New("outbox: el plazo de reserva debe ser mayor a 0")
+	}
+	slog.Info("Relay de eventos iniciado", slog.String("table", r.outbox.table))
+
+	for {
+		n, err := r.RelayOnce(r.ctx)
+		if err != nil && r.ctx.Err() == nil {
+			slog.Warn("Error al entregar los eventos pendientes", slog.String("error", err.Error()))
+		}
+
+		// Con un lote completo puede haber más eventos pendientes
+		wait := r.pollInterval
+		if err == nil && n == r.batchSize {
+			wait = 0
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-r.stop:
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Shutdown detiene el Relay después de terminar el lote en curso. Si `ctx` termina antes, se cancela el
+// lote y sus eventos se reintentarán
+func (r *Relay) Shutdown(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	if !r.started.Load() {
+		return nil
+	}
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-r.done
+		return ctx.Err()
+	}
+}
+
+// RelayOnce toma un lote de eventos pendientes, los entrega y registra el resultado de cada uno. Devuelve
+// la cantidad de eventos tomados
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for i, e := range events {
+		if err := r.deliver(ctx, e); err != nil {
+			if ctx.Err() != nil {
+				// Se interrumpió la entrega: los eventos restantes vuelven a estar disponibles de inmediato
+				r.release(ctx, events[i:])
+				return len(events), ctx.Err()
+			}
+			errs = append(errs, err)
+		}
+	}
+	return len(events), errors.Join(errs...)
+}
+
+// claim toma el lote en una transacción corta y lo reserva adelantando `next_attempt_at` al fin del plazo
+func (r *Relay) claim(ctx context.Context) ([]Event, error) {
+	const columns = "id, topic, payload, created_at, attempts"
+	const where = "delivered_at IS NULL AND attempts < ? AND next_attempt_at <= ?"
+
+	now := time.Now().UTC()
+	var query string
+	args := []any{r.maxAttempts, now, r.batchSize}
+	switch r.outbox.typo {
+	case config.DatabaseTypeMssql:
+		query = "SELECT TOP (?) " + columns + " FROM " + r.outbox.table + " WITH (UPDLOCK, READPAST, ROWLOCK) WHERE " + where + " ORDER BY id"
+		args = []any{r.batchSize, r.maxAttempts, now}
+	case config.DatabaseTypePostgres:
+		query = "SELECT " + columns + " FROM " + r.outbox.table + " WHERE " + where + " ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+	default:
+		query = "SELECT " + columns + " FROM " + r.outbox.table + " WHERE " + where + " ORDER BY id LIMIT ?"
+	}
+
+	scan := func(row database.Row) (Event, error) {
+		var e Event
+		var payload string
+		err := row.Scan(&e.ID, &e.Topic, &payload, &e.CreatedAt, &e.Attempts)
+		e.Payload = []byte(payload)
+		return e, err
+	}
+
+	var events []Event
+	err := database.RunInTx(database.WithDatabase(ctx, r.db), func(ctx context.Context) error {
+		tx, _ := database.TxFrom(ctx)
+
+		var err error
+		events, err = database.Collect(database.Stream(ctx, tx, scan, database.Rebind(r.outbox.typo, query), args...))
+		if err != nil || len(events) == 0 {
+			return err
+		}
+		return r.reschedule(ctx, tx, events, now.Add(r.lease))
+	})
+	return events, err
+}
+
+// release devuelve los eventos reservados que no se alcanzaron a entregar. Usa un contexto propio porque
+// se invoca cuando `ctx` ya fue cancelado
+func (r *Relay) release(ctx context.Context, events []Event) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := r.reschedule(ctx, r.db, events, time.Now().UTC()); err != nil {
+		slog.WarnContext(ctx, "No se pudieron liberar los eventos reservados; se reintentarán al vencer el plazo",
+			slog.Int("count", len(events)), slog.String("error", err.Error()))
+	}
+}
+
+// reschedule cambia el `next_attempt_at` de los eventos
+func (r *Relay) reschedule(ctx context.Context, q database.Querier, events []Event, at time.Time) error {
+	marks := make([]string, len(events))
+	args := make([]any, 0, len(events)+1)
+	args = append(args, at)
+	for i, e := range events {
+		marks[i] = "?"
+		args = append(args, e.ID)
+	}
+
+	query := "UPDATE " + r.outbox.table + " SET next_attempt_at = ? WHERE id IN (" + strings.Join(marks, ", ") + ")"
+	_, err := q.Exec(ctx, database.Rebind(r.outbox.typo, query), args...)
+	return err
+}
+
+// deliver publica el evento y registra la entrega o el fallo. Solo devuelve error si se cancela `ctx` o no
+// se puede registrar el resultado; en ese caso el evento se reintenta al vencer la reserva
+func (r *Relay) deliver(ctx context.Context, e Event) error {
+	now := time.Now().UTC()
+
+	if err := r.pub.Publish(ctx, e); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.WarnContext(ctx, "No se pudo entregar el evento",
+			slog.Int64("id", e.ID), slog.String("topic", e.Topic), slog.Int("attempt", e.Attempts+1), slog.String("error", err.Error()))
+
+		next := now.Add(r.retryIn(e.Attempts))
+		query := "UPDATE " + r.outbox.table + " SET attempts = attempts + 1, next_attempt_at = ?, last_error = ? WHERE id = ?"
+		return r.record(ctx, query, next, err.Error(), e.ID)
+	}
+
+	query := "UPDATE " + r.outbox.table + " SET attempts = attempts + 1, delivered_at = ?, last_error = NULL WHERE id = ?"
+	return r.record(ctx, query, now, e.ID)
+}
+
+// record registra el resultado de una entrega aunque `ctx` se cancele después de publicar el evento, para
+// no volver a entregarlo
+func (r *Relay) record(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx, database.Rebind(r.outbox.typo, query), args...)
+	return err
+}
+
+// retryIn devuelve la espera antes del siguiente intento tras `attempts` intentos previos
+func (r *Relay) retryIn(attempts int) time.Duration {
+	wait := r.minBackoff
+	for range attempts {
+		if wait >= r.maxBackoff {
+			break
+		}
+		wait *= 2
+	}
+	return min(wait, r.maxBackoff)
+}
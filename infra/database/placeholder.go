@@ -0,0 +1,106 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// Placeholder devuelve el marcador del parámetro `n` (base 1) según el tipo de base de datos:
+// `$n` para PostgreSQL, `@pn` para SQL Server y `?` para MySQL/MariaDB y SQLite
+func Placeholder(typo string, n int) string {
+	switch typo {
+	case config.DatabaseTypePostgres:
+		return "$" + strconv.Itoa(n)
+	case config.DatabaseTypeMssql:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// Rebind convierte una consulta escrita con marcadores `?` al estilo del tipo de base de datos. Los `?`
+// dentro de literales de texto ('...'), identificadores entre comillas ("...", `...` y, en SQL Server,
+// [...]) y comentarios se conservan sin cambios. En MySQL se respetan las comillas escapadas con `\`.
+//
+// `??` escribe un `?` literal en todos los tipos de base de datos, p. ej. para los operadores JSONB de
+// PostgreSQL (`data ?? 'clave'`, `??|`, `??&`), que de otro modo se convertirían en marcadores
+func Rebind(typo, query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch ch {
+		case '\'', '"', '`', '[':
+			if ch == '[' && typo != config.DatabaseTypeMssql {
+				// Fuera de SQL Server los corchetes son arreglos (`ARRAY[?]`, `tags[?]`), no identificadores
+				b.WriteByte(ch)
+				continue
+			}
+			end := closingQuote(ch)
+			j := i + 1
+			for j < len(query) && query[j] != end {
+				if query[j] == '\\' && ch != '`' && typo == config.DatabaseTypeMysql {
+					j++ // MySQL admite comillas escapadas con `\` dentro de los literales
+				}
+				j++
+			}
+			if j >= len(query) {
+				j = len(query) - 1
+			}
+			b.WriteString(query[i : j+1])
+			i = j
+		case '-':
+			if i+1 < len(query) && query[i+1] == '-' {
+				j := strings.IndexByte(query[i:], '\n')
+				if j < 0 {
+					j = len(query) - i - 1
+				}
+				b.WriteString(query[i : i+j+1])
+				i += j
+				continue
+			}
+			b.WriteByte(ch)
+		case '/':
+			if i+1 < len(query) && query[i+1] == '*' {
+				j := strings.Index(query[i+2:], "*/")
+				if j < 0 {
+					b.WriteString(query[i:])
+					return b.String()
+				}
+				b.WriteString(query[i : i+j+4])
+				i += j + 3
+				continue
+			}
+			b.WriteByte(ch)
+		case '?':
+			if i+1 < len(query) && query[i+1] == '?' {
+				b.WriteByte('?')
+				i++
+				continue
+			}
+			n++
+			b.WriteString(Placeholder(typo, n))
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String()
+}
+
+// closingQuote devuelve el carácter que cierra un literal o identificador
+func closingQuote(open byte) byte {
+	if open == '[' {
+		return ']'
+	}
+
+	return open
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// TestRebind valida la conversión de marcadores `?` al estilo de cada base de datos
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		typo     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "PostgreSQL",
+			typo:     config.DatabaseTypePostgres,
+			query:    "SELECT * FROM t WHERE a = ? AND b = ?",
+			expected: "SELECT * FROM t WHERE a = $1 AND b = $2",
+		},
+		{
+			name:     "SQL Server",
+			typo:     config.DatabaseTypeMssql,
+			query:    "SELECT * FROM t WHERE a = ? AND b = ?",
+			expected: "SELECT * FROM t WHERE a = @p1 AND b = @p2",
+		},
+		{
+			name:     "MySQL sin cambios",
+			typo:     config.DatabaseTypeMysql,
+			query:    "SELECT * FROM t WHERE a = ?",
+			expected: "SELECT * FROM t WHERE a = ?",
+		},
+		{
+			name:     "Literales e identificadores se conservan",
+			typo:     config.DatabaseTypeMssql,
+			query:    `SELECT '¿?', "col?", [x?] FROM t WHERE a = ?`,
+			expected: `SELECT '¿?', "col?", [x?] FROM t WHERE a = @p1`,
+		},
+		{
+			name:     "Constructor de arreglos en PostgreSQL",
+			typo:     config.DatabaseTypePostgres,
+			query:    "SELECT * FROM t WHERE tags && ARRAY[?, ?] AND id = ?",
+			expected: "SELECT * FROM t WHERE tags && ARRAY[$1, $2] AND id = $3",
+		},
+		{
+			name:     "Subíndice de arreglo en PostgreSQL",
+			typo:     config.DatabaseTypePostgres,
+			query:    "SELECT arr[?] FROM t WHERE id = ?",
+			expected: "SELECT arr[$1] FROM t WHERE id = $2",
+		},
+		{
+			name:     "Comilla escapada en MySQL",
+			typo:     config.DatabaseTypeMysql,
+			query:    `SELECT 'O\'Brien ??' FROM t WHERE a = ?? AND b = ?`,
+			expected: `SELECT 'O\'Brien ??' FROM t WHERE a = ? AND b = ?`,
+		},
+		{
+			name:     "Comentarios se conservan",
+			typo:     config.DatabaseTypeMssql,
+			query:    "SELECT 1 -- ¿?\nFROM t /* ? */ WHERE a = ?",
+			expected: "SELECT 1 -- ¿?\nFROM t /* ? */ WHERE a = @p1",
+		},
+		{
+			name:     "Operadores JSONB con ??",
+			typo:     config.DatabaseTypePostgres,
+			query:    "SELECT * FROM t WHERE data ?? 'a' AND data ??| array['b'] AND data ??& ? AND id = ?",
+			expected: "SELECT * FROM t WHERE data ? 'a' AND data ?| array['b'] AND data ?& $1 AND id = $2",
+		},
+		{
+			name:     "?? en MySQL",
+			typo:     config.DatabaseTypeMysql,
+			query:    "SELECT '??', ?? FROM t WHERE a = ?",
+			expected: "SELECT '??', ? FROM t WHERE a = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rebind(tt.typo, tt.query); got != tt.expected {
+				t.Errorf("Se esperaba %q, obtuvo: %q", tt.expected, got)
+			}
+		})
+	}
+}
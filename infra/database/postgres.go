@@ -3,9 +3,13 @@ package database
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/wfrscltech/vulcano/config"
 )
@@ -41,6 +45,7 @@ func newPostgresCnx(dcfg config.DatabaseConfig) (Database, error) {
 	defer cancel()
 	err = cnx.Ping(ctx)
 	if err != nil {
+		cnx.Close()
 		return nil, err
 	}
 
@@ -81,6 +86,12 @@ func (db *Postgres) BeginTx(ctx context.Context) (Tx, error) {
 	return &PostgresTx{tx}, nil
 }
 
+// BulkInsert carga las filas con el protocolo COPY de PostgreSQL
+func (db *Postgres) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	defer closeSource(src)
+	return copyFrom(ctx, db.pool, table, columns, src, opts)
+}
+
 func (db *Postgres) RawConnection() any {
 	return db.pool
 }
@@ -99,6 +110,39 @@ func (r *PostgresRows) Close() {
 	r.Rows.Close()
 }
 
+func (r *PostgresRows) Err() error {
+	return r.Rows.Err()
+}
+
+func (r *PostgresRows) Columns() ([]string, error) {
+	fds := r.Rows.FieldDescriptions()
+	names := make([]string, len(fds))
+	for i, fd := range fds {
+		names[i] = fd.Name
+	}
+	return names, nil
+}
+
+func (r *PostgresRows) ColumnTypes() ([]ColumnType, error) {
+	tm := pgTypeMap
+	if conn := r.Rows.Conn(); conn != nil {
+		tm = conn.TypeMap()
+	}
+
+	fds := r.Rows.FieldDescriptions()
+	types := make([]ColumnType, len(fds))
+	for i, fd := range fds {
+		types[i] = ColumnType{Name: fd.Name, DatabaseType: "UNKNOWN", ScanType: anyType}
+		if t, ok := tm.TypeForOID(fd.DataTypeOID); ok {
+			types[i].DatabaseType = strings.ToUpper(t.Name)
+			if st, ok := pgScanTypes[t.Name]; ok {
+				types[i].ScanType = st
+			}
+		}
+	}
+	return types, nil
+}
+
 func (r *PostgresRow) Scan(dest ...any) error {
 	return r.Row.Scan(dest...)
 }
@@ -125,6 +169,11 @@ func (tx *PostgresTx) Exec(ctx context.Context, query string, args ...any) (int6
 	return cmd.RowsAffected(), nil
 }
 
+func (tx *PostgresTx) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	defer closeSource(src)
+	return copyFrom(ctx, tx.Tx, table, columns, src, opts)
+}
+
 func (tx *PostgresTx) Commit(ctx context.Context) error {
 	return tx.Tx.Commit(ctx)
 }
@@ -133,10 +182,60 @@ func (tx *PostgresTx) Rollback(ctx context.Context) error {
 	return tx.Tx.Rollback(ctx)
 }
 
+// --- Tipos de columnas ---
+
+// pgTypeMap resuelve los OID de los tipos nativos cuando las filas no exponen su conexión
+var pgTypeMap = pgtype.NewMap()
+
+// pgScanTypes asocia los tipos nativos de PostgreSQL con el tipo Go que entrega pgx al escanear en `any`
+var pgScanTypes = map[string]reflect.Type{
+	"bool":        reflect.TypeFor[bool](),
+	"int2":        reflect.TypeFor[int16](),
+	"int4":        reflect.TypeFor[int32](),
+	"int8":        reflect.TypeFor[int64](),
+	"float4":      reflect.TypeFor[float32](),
+	"float8":      reflect.TypeFor[float64](),
+	"numeric":     reflect.TypeFor[pgtype.Numeric](),
+	"text":        reflect.TypeFor[string](),
+	"varchar":     reflect.TypeFor[string](),
+	"bpchar":      reflect.TypeFor[string](),
+	"name":        reflect.TypeFor[string](),
+	"json":        reflect.TypeFor[any](),
+	"jsonb":       reflect.TypeFor[any](),
+	"bytea":       reflect.TypeFor[[]byte](),
+	"uuid":        reflect.TypeFor[[16]byte](),
+	"date":        reflect.TypeFor[time.Time](),
+	"timestamp":   reflect.TypeFor[time.Time](),
+	"timestamptz": reflect.TypeFor[time.Time](),
+	"interval":    reflect.TypeFor[pgtype.Interval](),
+}
+
+// --- Carga masiva ---
+
+// copier es la parte común de pgxpool.Pool y pgx.Tx usada por la carga masiva
+type copier interface {
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+}
+
+func copyFrom(ctx context.Context, db copier, table string, columns []string, src RowSource, opts []BulkOption) (int64, error) {
+	if err := validateBulkTarget(table, columns); err != nil {
+		return 0, err
+	}
+
+	ps := &progressSource{RowSource: src, opts: newBulkOptions(opts)}
+	n, err := db.CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, ps)
+	if err != nil {
+		return n, err
+	}
+
+	ps.done()
+	return n, nil
+}
+
 // --- Adaptador de conexión ---
 
 func psqldsn(dcfg config.DatabaseConfig) string {
-	return fmt.Sprintf(
+	dsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
 		dcfg.User,
 		dcfg.Password,
@@ -144,4 +243,10 @@ func psqldsn(dcfg config.DatabaseConfig) string {
 		dcfg.Port,
 		dcfg.Name,
 	)
+
+	// pgx envía los parámetros desconocidos de la cadena de conexión como parámetros de la sesión
+	if dcfg.Schema != "" {
+		dsn += "&search_path=" + url.QueryEscape(dcfg.Schema)
+	}
+	return dsn
 }
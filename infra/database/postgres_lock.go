@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Los nombres se convierten en la clave numérica del advisory lock con el hash del servidor, de modo
+// que todos los clientes obtienen la misma clave
+const (
+	pgTryLock     = "SELECT pg_try_advisory_lock(hashtextextended($1, 0))"
+	pgUnlock      = "SELECT pg_advisory_unlock(hashtextextended($1, 0))"
+	pgTryXactLock = "SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))"
+)
+
+// Largo máximo del nombre de un bloqueo en PostgreSQL; el nombre se reduce a un hash
+const pgLockNameMax = 1024
+
+// TryLock intenta obtener un advisory lock de sesión en una conexión dedicada del pool
+func (db *Postgres) TryLock(ctx context.Context, name string) (*Lock, bool, error) {
+	if err := validateLockName(name, pgLockNameMax); err != nil {
+		return nil, false, err
+	}
+
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ok, err := pgTryLockOn(ctx, conn, name)
+	if err != nil || !ok {
+		conn.Release()
+		return nil, false, err
+	}
+
+	return pgSessionLock(conn, name), true, nil
+}
+
+// Lock espera un advisory lock de sesión consultando periódicamente, para no mantener una consulta
+// bloqueada que aborte la conexión al cancelarse
+func (db *Postgres) Lock(ctx context.Context, name string, timeout time.Duration) (*Lock, error) {
+	if err := validateLockName(name, pgLockNameMax); err != nil {
+		return nil, err
+	}
+
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pollLock(ctx, timeout, func(ctx context.Context) (bool, error) {
+		return pgTryLockOn(ctx, conn, name)
+	})
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return pgSessionLock(conn, name), nil
+}
+
+func pgTryLockOn(ctx context.Context, conn *pgxpool.Conn, name string) (bool, error) {
+	var ok bool
+	err := conn.QueryRow(ctx, pgTryLock, name).Scan(&ok)
+	return ok, err
+}
+
+// pgSessionLock libera el bloqueo y devuelve la conexión al pool; si no se puede liberar, la conexión se
+// cierra para que el servidor descarte el bloqueo
+func pgSessionLock(conn *pgxpool.Conn, name string) *Lock {
+	return &Lock{name: name, release: func(ctx context.Context) error {
+		var released bool
+		if err := conn.QueryRow(ctx, pgUnlock, name).Scan(&released); err != nil {
+			raw := conn.Hijack()
+			raw.Close(context.Background())
+			return err
+		}
+		conn.Release()
+		return nil
+	}}
+}
+
+// TryLock intenta obtener un advisory lock de transacción
+func (tx *PostgresTx) TryLock(ctx context.Context, name string) (*Lock, bool, error) {
+	if err := validateLockName(name, pgLockNameMax); err != nil {
+		return nil, false, err
+	}
+
+	ok, err := tx.tryXactLock(ctx, name)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &Lock{name: name}, true, nil
+}
+
+// Lock espera un advisory lock de transacción consultando periódicamente, ya que una espera cancelada
+// abortaría la transacción
+func (tx *PostgresTx) Lock(ctx context.Context, name string, timeout time.Duration) (*Lock, error) {
+	if err := validateLockName(name, pgLockNameMax); err != nil {
+		return nil, err
+	}
+
+	err := pollLock(ctx, timeout, func(ctx context.Context) (bool, error) {
+		return tx.tryXactLock(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{name: name}, nil
+}
+
+func (tx *PostgresTx) tryXactLock(ctx context.Context, name string) (bool, error) {
+	var ok bool
+	err := tx.Tx.QueryRow(ctx, pgTryXactLock, name).Scan(&ok)
+	return ok, err
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Listen se suscribe a los canales con LISTEN sobre una conexión dedicada, fuera del pool
+func (db *Postgres) Listen(ctx context.Context, channels ...string) (<-chan Notification, error) {
+	if err := validateChannels(channels); err != nil {
+		return nil, err
+	}
+
+	// La primera conexión se establece de forma sincrónica para informar errores de configuración
+	conn, err := db.listen(ctx, channels)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Notification, notifyBuffer)
+	go func() {
+		defer close(out)
+
+		wait := retryMinBackoff
+		for {
+			if conn != nil {
+				err = db.receive(ctx, conn, out)
+				conn.Close(context.Background())
+				if ctx.Err() != nil {
+					return
+				}
+				logNotifyError("Se perdió la conexión de notificaciones de PostgreSQL", err, wait)
+			}
+
+			var ok bool
+			if wait, ok = backoff(ctx, wait); !ok {
+				return
+			}
+
+			if conn, err = db.listen(ctx, channels); err != nil {
+				conn = nil
+				logNotifyError("Error al reconectar las notificaciones de PostgreSQL", err, wait)
+				continue
+			}
+			wait = retryMinBackoff
+		}
+	}()
+
+	return out, nil
+}
+
+// Notify publica el aviso con pg_notify
+func (db *Postgres) Notify(ctx context.Context, channel, payload string) error {
+	if err := validateChannels([]string{channel}); err != nil {
+		return err
+	}
+
+	_, err := db.pool.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
+
+// listen abre una conexión dedicada y se suscribe a los canales
+func (db *Postgres) listen(ctx context.Context, channels []string) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, db.pool.Config().ConnConfig.Copy())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ch := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{ch}.Sanitize()); err != nil {
+			conn.Close(context.Background())
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// receive entrega los avisos de la conexión hasta que se pierda o termine el contexto
+func (db *Postgres) receive(ctx context.Context, conn *pgx.Conn, out chan<- Notification) error {
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if !deliver(ctx, out, Notification{Channel: n.Channel, Payload: n.Payload}) {
+			return ctx.Err()
+		}
+	}
+}
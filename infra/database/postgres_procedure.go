@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// pgQueryer es la parte común de *pgxpool.Pool y pgx.Tx usada para ejecutar procedimientos
+type pgQueryer interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// pgIsProcedure indica si el nombre corresponde a un procedimiento (CALL) o a una función (SELECT). Los
+// nombres sin esquema se buscan en el search_path
+const pgIsProcedure = `SELECT EXISTS (
+	SELECT 1 FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE p.prokind = 'p' AND p.proname = $1
+	  AND (n.nspname = $2 OR ($2 = '' AND n.nspname = ANY (current_schemas(false))))
+)`
+
+// CallProcedure ejecuta un procedimiento con CALL o una función con `SELECT * FROM`. Los procedimientos
+// admiten parámetros OUT e INOUT, que se leen de la fila devuelta por CALL; las funciones devuelven su
+// resultado como un único conjunto de resultados
+func (db *Postgres) CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error) {
+	return callPgProcedure(ctx, db.pool, name, params)
+}
+
+func (tx *PostgresTx) CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error) {
+	return callPgProcedure(ctx, tx.Tx, name, params)
+}
+
+func callPgProcedure(ctx context.Context, q pgQueryer, name string, params []Param) (*Results, error) {
+	if err := validateProcedure(name, params, false); err != nil {
+		return nil, err
+	}
+
+	schema, proc := "", name
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		schema, proc = name[:i], name[i+1:]
+	}
+
+	// Los identificadores sin comillas se guardan en minúsculas en el catálogo
+	var isProc bool
+	if err := q.QueryRow(ctx, pgIsProcedure, strings.ToLower(proc), strings.ToLower(schema)).Scan(&isProc); err != nil {
+		return nil, err
+	}
+
+	call, args, outs := pgCallArgs(params)
+	if !isProc {
+		if len(outs) > 0 {
+			return nil, errOutOnFunction
+		}
+		rows, err := q.Query(ctx, fmt.Sprintf("SELECT * FROM %s(%s)", name, call), args...)
+		if err != nil {
+			return nil, err
+		}
+		return &Results{rows: &PostgresRows{rows}}, nil
+	}
+
+	rows, err := q.Query(ctx, fmt.Sprintf("CALL %s(%s)", name, call), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() && len(outs) > 0 {
+		if err := rows.Scan(outs...); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Results{}, nil
+}
+
+// pgCallArgs arma la lista de argumentos de la llamada. Los parámetros OUT se envían como NULL, según lo
+// exige CALL, y sus destinos se devuelven en el orden de las columnas del resultado
+func pgCallArgs(params []Param) (string, []any, []any) {
+	parts := make([]string, len(params))
+	args := make([]any, 0, len(params))
+	var outs []any
+
+	for i, p := range params {
+		value := "NULL"
+		switch p.Dir {
+		case DirIn:
+			args = append(args, p.Value)
+			value = fmt.Sprintf("$%d", len(args))
+		case DirInOut:
+			args = append(args, reflect.ValueOf(p.Dest).Elem().Interface())
+			value = fmt.Sprintf("$%d", len(args))
+			outs = append(outs, p.Dest)
+		case DirOut:
+			outs = append(outs, p.Dest)
+		}
+
+		parts[i] = value
+		if p.Name != "" {
+			parts[i] = p.Name + " => " + value
+		}
+	}
+
+	return strings.Join(parts, ", "), args, outs
+}
@@ -0,0 +1,61 @@
+package database
+
+import "context"
+
+// Consultas de catálogo de PostgreSQL; las columnas de information_schema usan dominios propios, por lo
+// que se convierten a tipos nativos
+var pgSchemaQueries = schemaQueries{
+	tables: `SELECT table_schema::text, table_name::text, table_type::text
+FROM information_schema.tables
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+  AND ($1::text = '' OR table_schema = $1::text)
+ORDER BY table_schema, table_name`,
+
+	table: `SELECT table_schema::text, table_name::text, table_type::text
+FROM information_schema.tables
+WHERE table_schema = COALESCE(NULLIF($1::text, ''), current_schema()) AND table_name = $2::text`,
+
+	columns: `SELECT column_name::text, upper(udt_name::text), is_nullable = 'YES', column_default::text,
+  character_maximum_length::int,
+  CASE WHEN data_type = 'numeric' THEN numeric_precision::int END,
+  CASE WHEN data_type = 'numeric' THEN numeric_scale::int END,
+  ordinal_position::int,
+  is_identity = 'YES' OR COALESCE(column_default::text, '') LIKE 'nextval(%'
+FROM information_schema.columns
+WHERE table_schema = $1::text AND table_name = $2::text
+ORDER BY ordinal_position`,
+
+	indexes: `SELECT i.relname::text, ix.indisunique, ix.indisprimary, a.attname::text
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_class i ON i.oid = ix.indexrelid
+CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = $1::text AND t.relname = $2::text AND k.ord <= ix.indnkeyatts
+ORDER BY i.relname, k.ord`,
+
+	foreignKeys: `SELECT c.conname::text, rn.nspname::text, rt.relname::text, a.attname::text, ra.attname::text,
+  CASE c.confdeltype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END,
+  CASE c.confupdtype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_class rt ON rt.oid = c.confrelid
+JOIN pg_namespace rn ON rn.oid = rt.relnamespace
+CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refnum, ord)
+JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refnum
+WHERE c.contype = 'f' AND n.nspname = $1::text AND t.relname = $2::text
+ORDER BY c.conname, k.ord`,
+}
+
+// Tables lista las tablas y vistas consultando information_schema
+func (db *Postgres) Tables(ctx context.Context, schema string) ([]TableRef, error) {
+	return listTables(ctx, db, pgSchemaQueries, schema)
+}
+
+// DescribeTable describe la tabla consultando information_schema y pg_catalog
+func (db *Postgres) DescribeTable(ctx context.Context, schema, table string) (*Table, error) {
+	return describeTable(ctx, db, pgSchemaQueries, schema, table)
+}
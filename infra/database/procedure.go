@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"regexp"
+)
+
+// Dirección de un parámetro de un procedimiento almacenado
+type ParamDir int
+
+const (
+	// Parámetro de entrada
+	DirIn ParamDir = iota
+	// Parámetro de salida (OUTPUT en SQL Server, OUT en PostgreSQL)
+	DirOut
+	// Parámetro de entrada y salida (OUTPUT con valor inicial en SQL Server, INOUT en PostgreSQL)
+	DirInOut
+)
+
+// Param es un parámetro de un procedimiento almacenado
+type Param struct {
+	// Nombre del parámetro, sin `@`. Obligatorio en SQL Server; en PostgreSQL se usa la notación
+	// `nombre => valor` cuando se indica
+	Name string
+	// Valor de un parámetro de entrada
+	Value any
+	// Puntero que recibe el valor de un parámetro de salida; en los de entrada y salida su valor actual se
+	// envía como valor inicial
+	Dest any
+	// Dirección del parámetro
+	Dir ParamDir
+}
+
+// In crea un parámetro de entrada
+func In(name string, value any) Param {
+	return Param{Name: name, Value: value, Dir: DirIn}
+}
+
+// Out crea un parámetro de salida que se escribe en el puntero `dest`
+func Out(name string, dest any) Param {
+	return Param{Name: name, Dest: dest, Dir: DirOut}
+}
+
+// InOut crea un parámetro de entrada y salida; el valor apuntado por `dest` se envía y se reemplaza por
+// el valor devuelto
+func InOut(name string, dest any) Param {
+	return Param{Name: name, Dest: dest, Dir: DirInOut}
+}
+
+// ProcedureCaller es la capacidad de ejecutar procedimientos almacenados. La implementan MSSQL y Postgres,
+// tanto sobre la conexión como dentro de una transacción
+type ProcedureCaller interface {
+	// CallProcedure ejecuta el procedimiento `name` (opcionalmente calificado con el esquema). Los
+	// parámetros de salida y el código de retorno quedan disponibles después de Results.Close
+	CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error)
+}
+
+// CallProcedure ejecuta un procedimiento almacenado con `q`, que puede ser la conexión o una transacción
+// (ver From):
+//
+//	var total float64
+//	res, err := database.CallProcedure(ctx, database.From(ctx), "ventas.sp_resumen",
+//		database.In("Desde", desde),
+//		database.Out("Total", &total),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer res.Close()
+//	for rows, err := range res.Sets() {
+//		...
+//	}
+//	if err := res.Close(); err != nil {
+//		return err
+//	}
+//	// total y res.ReturnCode() ya están disponibles
+func CallProcedure(ctx context.Context, q Querier, name string, params ...Param) (*Results, error) {
+	pc, ok := q.(ProcedureCaller)
+	if !ok {
+		return nil, fmt.Errorf("procedimientos: el motor %T no soporta procedimientos almacenados", q)
+	}
+	return pc.CallProcedure(ctx, name, params...)
+}
+
+// paramNameRe valida los nombres de los parámetros, que se escriben en el texto de la llamada
+var paramNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateProcedure valida el nombre del procedimiento y sus parámetros
+func validateProcedure(name string, params []Param, namesRequired bool) error {
+	if !identifierRe.MatchString(name) {
+		return fmt.Errorf("procedimientos: el nombre `%s` no es válido", name)
+	}
+
+	for i, p := range params {
+		switch {
+		case p.Name == "" && namesRequired:
+			return fmt.Errorf("procedimientos: el parámetro #%d de `%s` requiere un nombre", i+1, name)
+		case p.Name != "" && !paramNameRe.MatchString(p.Name):
+			return fmt.Errorf("procedimientos: el nombre de parámetro `%s` no es válido", p.Name)
+		case p.Dir != DirIn && !isPointer(p.Dest):
+			return fmt.Errorf("procedimientos: el parámetro `%s` de salida requiere un puntero no nulo", p.Name)
+		}
+	}
+
+	return nil
+}
+
+func isPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && !rv.IsNil()
+}
+
+// Results representa los conjuntos de resultados de un procedimiento almacenado. Se debe invocar Close
+// al terminar; los parámetros de salida y el código de retorno se completan recién entonces
+type Results struct {
+	rows     Rows
+	next     func() bool
+	finish   func() error
+	code     int32
+	iterated bool
+	closed   bool
+	err      error
+}
+
+// Sets itera los conjuntos de resultados en orden, omitiendo los que no tienen columnas. Cada Rows es
+// válido solo hasta avanzar al siguiente conjunto y no se debe cerrar; solo se puede iterar una vez
+func (r *Results) Sets() iter.Seq2[Rows, error] {
+	return func(yield func(Rows, error) bool) {
+		if r.rows == nil || r.iterated || r.closed {
+			return
+		}
+		r.iterated = true
+
+		for {
+			cols, err := r.rows.Columns()
+			if err != nil {
+				r.err = err
+				yield(nil, err)
+				return
+			}
+			if len(cols) > 0 && !yield(resultSet{r.rows}, nil) {
+				return
+			}
+			if err := r.rows.Err(); err != nil {
+				r.err = err
+				yield(nil, err)
+				return
+			}
+			if r.next == nil || !r.next() {
+				if err := r.rows.Err(); err != nil {
+					r.err = err
+					yield(nil, err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// Close libera los conjuntos de resultados pendientes y completa los parámetros de salida
+func (r *Results) Close() error {
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+
+	if r.rows != nil {
+		if err := r.rows.Err(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.rows.Close()
+	}
+	if r.finish != nil {
+		if err := r.finish(); err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+
+	return r.err
+}
+
+// ReturnCode devuelve el código de retorno del procedimiento (`RETURN n` en SQL Server; 0 en PostgreSQL).
+// Solo es válido después de Close
+func (r *Results) ReturnCode() int {
+	return int(r.code)
+}
+
+// resultSet expone un conjunto de resultados sin permitir que el llamador cierre los restantes
+type resultSet struct {
+	Rows
+}
+
+func (resultSet) Close() {}
+
+// errOutOnFunction indica que se pidieron parámetros de salida a una función de PostgreSQL
+var errOutOnFunction = errors.New("procedimientos: las funciones de PostgreSQL devuelven sus valores de salida como columnas del conjunto de resultados; use parámetros de entrada")
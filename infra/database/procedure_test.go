@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateProcedure(t *testing.T) {
+	var total int
+	cases := []struct {
+		name          string
+		proc          string
+		params        []Param
+		namesRequired bool
+		wantErr       bool
+	}{
+		{"válido", "ventas.sp_resumen", []Param{In("Desde", 1), Out("Total", &total)}, true, false},
+		{"nombre inválido", "sp; DROP TABLE x", nil, false, true},
+		{"parámetro sin nombre en SQL Server", "sp", []Param{{Value: 1}}, true, true},
+		{"parámetro sin nombre en PostgreSQL", "sp", []Param{{Value: 1}}, false, false},
+		{"nombre de parámetro inválido", "sp", []Param{In("@x", 1)}, false, true},
+		{"salida sin puntero", "sp", []Param{Out("Total", total)}, false, true},
+		{"salida con puntero nulo", "sp", []Param{Out("Total", (*int)(nil))}, false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateProcedure(tc.proc, tc.params, tc.namesRequired)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateProcedure() error = %v, se esperaba error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPgCallArgs(t *testing.T) {
+	var total float64
+	count := 3
+	call, args, outs := pgCallArgs([]Param{
+		In("desde", "2024-01-01"),
+		Out("total", &total),
+		InOut("cantidad", &count),
+		{Value: true},
+	})
+
+	if call != "desde => $1, total => NULL, cantidad => $2, $3" {
+		t.Errorf("call = %q", call)
+	}
+	if !reflect.DeepEqual(args, []any{"2024-01-01", 3, true}) {
+		t.Errorf("args = %v", args)
+	}
+	if len(outs) != 2 || outs[0] != &total || outs[1] != &count {
+		t.Errorf("outs = %v", outs)
+	}
+}
+
+func TestCallProcedureUnsupported(t *testing.T) {
+	if _, err := CallProcedure(context.Background(), &SQLite{}, "sp"); err == nil {
+		t.Fatal("se esperaba un error para un motor sin procedimientos")
+	}
+}
+
+// multiRows simula un resultado con varios conjuntos de registros
+type multiRows struct {
+	sets   [][]string
+	set    int
+	closed bool
+	err    error
+}
+
+func (r *multiRows) Next() bool                         { return false }
+func (r *multiRows) Scan(...any) error                  { return nil }
+func (r *multiRows) Close()                             { r.closed = true }
+func (r *multiRows) Err() error                         { return r.err }
+func (r *multiRows) Columns() ([]string, error)         { return r.sets[r.set], nil }
+func (r *multiRows) ColumnTypes() ([]ColumnType, error) { return nil, nil }
+func (r *multiRows) nextSet() bool                      { r.set++; return r.set < len(r.sets) }
+
+func TestResultsSets(t *testing.T) {
+	rows := &multiRows{sets: [][]string{{"id"}, {}, {"total", "moneda"}}}
+	finished := false
+	res := &Results{rows: rows, next: rows.nextSet, finish: func() error { finished = true; return nil }, code: 4}
+
+	var got [][]string
+	for set, err := range res.Sets() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		cols, _ := set.Columns()
+		got = append(got, cols)
+		set.Close()
+	}
+
+	if !reflect.DeepEqual(got, [][]string{{"id"}, {"total", "moneda"}}) {
+		t.Errorf("conjuntos = %v; se esperaba omitir el conjunto sin columnas", got)
+	}
+	if rows.closed {
+		t.Error("cerrar un conjunto no debe cerrar el resultado completo")
+	}
+
+	if err := res.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !rows.closed || !finished {
+		t.Error("Close debe cerrar los registros y completar los parámetros de salida")
+	}
+	if res.ReturnCode() != 4 {
+		t.Errorf("ReturnCode() = %d", res.ReturnCode())
+	}
+}
+
+func TestResultsSetsError(t *testing.T) {
+	boom := errors.New("falla")
+	rows := &multiRows{sets: [][]string{{"id"}, {"x"}}, err: boom}
+	res := &Results{rows: rows, next: rows.nextSet}
+
+	var errs []error
+	for _, err := range res.Sets() {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], boom) {
+		t.Fatalf("errores = %v", errs)
+	}
+	if err := res.Close(); !errors.Is(err, boom) {
+		t.Fatalf("Close() = %v", err)
+	}
+}
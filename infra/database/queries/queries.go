@@ -0,0 +1,286 @@
+// Package queries carga consultas SQL con nombre desde archivos `.sql`, típicamente embebidos con
+// `embed.FS`, para no escribir sentencias largas dentro del código Go.
+//
+// Cada consulta comienza con un comentario `-- name:` y puede indicar el motor al que aplica con
+// `-- dialect:`; las consultas sin dialecto aplican a todos los motores que no tengan una variante propia:
+//
+//	-- name: GetCustomer
+//	SELECT id, nombre FROM clientes WHERE id = $1
+//
+//	-- name: GetCustomer
+//	-- dialect: mssql
+//	SELECT id, nombre FROM clientes WHERE id = @p1
+//
+// Los repositorios obtienen sus consultas con Registry.Query y el servicio invoca Registry.Validate al
+// iniciar, de modo que una consulta faltante para el motor configurado se detecta antes de atender
+// peticiones:
+//
+//	//go:embed sql/*.sql
+//	var sqlFS embed.FS
+//
+//	var reg = queries.MustLoad(sqlFS, "sql/*.sql")
+//	var getCustomer = reg.Query("GetCustomer")
+//
+//	// Al iniciar
+//	if err := reg.Validate(cfg.Database.Typo); err != nil {
+//		return err
+//	}
+//
+//	// En el repositorio
+//	row := db.QueryRow(ctx, getCustomer.SQL(), id)
+package queries
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// Dialectos admitidos en `-- dialect:`, iguales a los valores de config.DatabaseConfig.Typo
+var dialects = []string{
+	config.DatabaseTypePostgres,
+	config.DatabaseTypeMssql,
+	config.DatabaseTypeSqlite,
+	config.DatabaseTypeMysql,
+}
+
+var (
+	nameRe    = regexp.MustCompile(`^--\s*name:\s*(\S+)\s*$`)
+	dialectRe = regexp.MustCompile(`^--\s*dialect:\s*(\S+)\s*$`)
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Registry contiene las consultas cargadas y las referencias hechas por los repositorios
+type Registry struct {
+	mu sync.RWMutex
+	// nombre -> dialecto ("" para la variante genérica) -> consulta
+	queries map[string]map[string]string
+	refs    map[string]*Query
+	dialect string
+}
+
+// Query es una referencia a una consulta con nombre. Su SQL se resuelve con el dialecto indicado en
+// Registry.Validate
+type Query struct {
+	name string
+	reg  *Registry
+}
+
+// Load lee los archivos de `fsys` que coinciden con los patrones (ver fs.Glob) y registra sus consultas
+func Load(fsys fs.FS, patterns ...string) (*Registry, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"*.sql"}
+	}
+
+	r := &Registry{queries: map[string]map[string]string{}, refs: map[string]*Query{}}
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("queries: patrón `%s` inválido: %w", pattern, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("queries: el patrón `%s` no coincide con ningún archivo", pattern)
+		}
+
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+			if err := r.parse(file, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// MustLoad es como Load pero entra en pánico ante un error; pensado para inicializar variables de paquete
+func MustLoad(fsys fs.FS, patterns ...string) *Registry {
+	r, err := Load(fsys, patterns...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// parse registra las consultas de un archivo
+func (r *Registry) parse(file string, data []byte) error {
+	var (
+		name, dialect string
+		start         int
+		body          strings.Builder
+		inHeader      bool
+	)
+
+	flush := func() error {
+		if name == "" {
+			return nil
+		}
+		sql := strings.TrimSpace(body.String())
+		if sql == "" {
+			return fmt.Errorf("queries: %s:%d: la consulta `%s` está vacía", file, start, name)
+		}
+		if _, ok := r.queries[name][dialect]; ok {
+			return fmt.Errorf("queries: %s:%d: la consulta `%s` %s está duplicada", file, start, name, describe(dialect))
+		}
+		if r.queries[name] == nil {
+			r.queries[name] = map[string]string{}
+		}
+		r.queries[name][dialect] = sql
+		body.Reset()
+		return nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if m := nameRe.FindStringSubmatch(trimmed); m != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+			if !validName.MatchString(m[1]) {
+				return fmt.Errorf("queries: %s:%d: el nombre `%s` no es válido", file, n, m[1])
+			}
+			name, dialect, start, inHeader = m[1], "", n, true
+			continue
+		}
+
+		if m := dialectRe.FindStringSubmatch(trimmed); m != nil && inHeader {
+			if !slices.Contains(dialects, m[1]) {
+				return fmt.Errorf("queries: %s:%d: el dialecto `%s` no es válido. Las opciones válidas son: %q", file, n, m[1], dialects)
+			}
+			dialect = m[1]
+			continue
+		}
+
+		if name == "" {
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return fmt.Errorf("queries: %s:%d: se encontró SQL antes del primer `-- name:`", file, n)
+			}
+			continue
+		}
+
+		if trimmed != "" {
+			inHeader = false
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("queries: %s: %w", file, err)
+	}
+
+	return flush()
+}
+
+// Query devuelve la referencia a la consulta `name` y la registra para que Validate compruebe que existe
+func (r *Registry) Query(name string) *Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if q, ok := r.refs[name]; ok {
+		return q
+	}
+	q := &Query{name: name, reg: r}
+	r.refs[name] = q
+	return q
+}
+
+// Validate fija el dialecto del registro (el valor de config.DatabaseConfig.Typo) y comprueba que todas
+// las consultas referenciadas con Query existan para él
+func (r *Registry) Validate(dialect string) error {
+	if !slices.Contains(dialects, dialect) {
+		return fmt.Errorf("queries: el dialecto `%s` no es válido. Las opciones válidas son: %q", dialect, dialects)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var missing []string
+	for name := range r.refs {
+		if _, ok := r.lookup(name, dialect); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("queries: no existen para %s las consultas: %s", dialect, strings.Join(missing, ", "))
+	}
+
+	r.dialect = dialect
+	return nil
+}
+
+// Get devuelve el SQL de la consulta `name` para el dialecto validado
+func (r *Registry) Get(name string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.dialect == "" {
+		return "", errors.New("queries: se debe invocar Validate antes de usar las consultas")
+	}
+	sql, ok := r.lookup(name, r.dialect)
+	if !ok {
+		return "", fmt.Errorf("queries: no existe la consulta `%s` para %s", name, r.dialect)
+	}
+	return sql, nil
+}
+
+// Names devuelve los nombres de las consultas cargadas, ordenados
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup busca la variante del dialecto o, en su defecto, la genérica
+func (r *Registry) lookup(name, dialect string) (string, bool) {
+	variants := r.queries[name]
+	if sql, ok := variants[dialect]; ok {
+		return sql, true
+	}
+	sql, ok := variants[""]
+	return sql, ok
+}
+
+// Name devuelve el nombre de la consulta
+func (q *Query) Name() string {
+	return q.name
+}
+
+// SQL devuelve el texto de la consulta para el dialecto validado. Entra en pánico si no se invocó
+// Validate, ya que indica un error de inicialización del servicio
+func (q *Query) SQL() string {
+	sql, err := q.reg.Get(q.name)
+	if err != nil {
+		panic(err)
+	}
+	return sql
+}
+
+func describe(dialect string) string {
+	if dialect == "" {
+		return "genérica"
+	}
+	return "para " + dialect
+}
@@ -0,0 +1,100 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var sqlFS = fstest.MapFS{
+	"sql/clientes.sql": {Data: []byte(`-- Consultas de clientes
+
+-- name: GetCustomer
+SELECT id, nombre
+FROM clientes
+WHERE id = $1;
+
+-- name: GetCustomer
+-- dialect: mssql
+SELECT id, nombre FROM clientes WHERE id = @p1;
+
+-- name: ListCustomers
+-- Lista paginada
+SELECT id, nombre FROM clientes ORDER BY id
+`)},
+	"sql/pedidos.sql": {Data: []byte(`-- name: CountOrders
+-- dialect: mssql
+SELECT COUNT(*) FROM pedidos WITH (NOLOCK)
+`)},
+}
+
+func TestLoadAndValidate(t *testing.T) {
+	reg, err := Load(sqlFS, "sql/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := reg.Query("GetCustomer")
+	list := reg.Query("ListCustomers")
+
+	if err := reg.Validate("postgres"); err != nil {
+		t.Fatal(err)
+	}
+	if got := get.SQL(); got != "SELECT id, nombre\nFROM clientes\nWHERE id = $1;" {
+		t.Errorf("GetCustomer (postgres) = %q", got)
+	}
+	if got := list.SQL(); got != "-- Lista paginada\nSELECT id, nombre FROM clientes ORDER BY id" {
+		t.Errorf("ListCustomers = %q", got)
+	}
+
+	if err := reg.Validate("mssql"); err != nil {
+		t.Fatal(err)
+	}
+	if got := get.SQL(); got != "SELECT id, nombre FROM clientes WHERE id = @p1;" {
+		t.Errorf("GetCustomer (mssql) = %q", got)
+	}
+
+	if got := strings.Join(reg.Names(), ","); got != "CountOrders,GetCustomer,ListCustomers" {
+		t.Errorf("Names() = %s", got)
+	}
+}
+
+func TestValidateMissing(t *testing.T) {
+	reg := MustLoad(sqlFS, "sql/*.sql")
+	reg.Query("CountOrders")
+	reg.Query("DeleteCustomer")
+
+	err := reg.Validate("postgres")
+	if err == nil || !strings.Contains(err.Error(), "CountOrders, DeleteCustomer") {
+		t.Fatalf("Validate() = %v; se esperaba informar las consultas faltantes", err)
+	}
+
+	if _, err := reg.Get("GetCustomer"); err == nil {
+		t.Fatal("se esperaba un error al usar el registro sin validar")
+	}
+	if err := reg.Validate("oracle"); err == nil {
+		t.Fatal("se esperaba un error con un dialecto inválido")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := map[string]string{
+		"SQL antes del nombre": "SELECT 1\n-- name: A\nSELECT 2",
+		"consulta vacía":       "-- name: A\n\n-- name: B\nSELECT 1",
+		"duplicada":            "-- name: A\nSELECT 1\n-- name: A\nSELECT 2",
+		"dialecto inválido":    "-- name: A\n-- dialect: oracle\nSELECT 1",
+		"nombre inválido":      "-- name: get-customer\nSELECT 1",
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(fstest.MapFS{"q.sql": {Data: []byte(content)}}); err == nil {
+				t.Fatal("se esperaba un error")
+			}
+		})
+	}
+
+	if _, err := Load(sqlFS, "otros/*.sql"); err == nil {
+		t.Fatal("se esperaba un error con un patrón sin archivos")
+	}
+}
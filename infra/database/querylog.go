@@ -0,0 +1,311 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// QueryInfo describe una sentencia ejecutada
+type QueryInfo struct {
+	// Texto de la sentencia; en las cargas masivas, `BULK INSERT <tabla>`
+	SQL string
+	// Cantidad de argumentos
+	Args int
+	// Duración de la ejecución; en las consultas de varias filas no incluye la lectura de los registros
+	Duration time.Duration
+	// Error de la ejecución, si lo hubo
+	Err error
+	// Indica si la sentencia se ejecutó dentro de una transacción
+	InTx bool
+}
+
+// QueryHook recibe cada sentencia ejecutada con el contexto de quien la ejecutó, de modo que los logs
+// hechos con slog.*Context incluyen el identificador de la petición (ver logger.ContextHandler)
+type QueryHook func(ctx context.Context, q QueryInfo)
+
+// LogQueries devuelve un QueryHook que registra cada sentencia con slog en el nivel `level`. Las
+// sentencias fallidas se registran al menos en el nivel Warn
+func LogQueries(level slog.Level) QueryHook {
+	return func(ctx context.Context, q QueryInfo) {
+		attrs := []slog.Attr{
+			slog.String("sql", q.SQL),
+			slog.Int("args", q.Args),
+			slog.Duration("duration", q.Duration),
+			slog.Bool("tx", q.InTx),
+		}
+		lvl := level
+		if q.Err != nil && !IsNoRows(q.Err) && !errors.Is(q.Err, context.Canceled) {
+			lvl = max(level, slog.LevelWarn)
+			attrs = append(attrs, slog.String("error", q.Err.Error()))
+		}
+		slog.LogAttrs(ctx, lvl, "Sentencia ejecutada", attrs...)
+	}
+}
+
+// WithQueryHook devuelve la conexión `db` con `hook` invocado tras cada sentencia, incluidas las de sus
+// transacciones. La conexión devuelta implementa las mismas capacidades opcionales que `db` (Notifier,
+// ProcedureCaller, Locker e Inspector), de modo que sus comprobaciones no cambian. New y Open la
+// aplican con LogQueries(slog.LevelDebug) cuando la configuración activa `logQueries`
+func WithQueryHook(db Database, hook QueryHook) Database {
+	h := &hookedDatabase{db: db, hook: hook}
+	c := hookedCaller{q: db, hook: hook}
+
+	// Cada combinación de capacidades necesita su propio tipo para que las comprobaciones de interfaz
+	// (`db.(database.Notifier)`) den el mismo resultado que con la conexión original
+	n, isNotifier := db.(Notifier)
+	_, isCaller := db.(ProcedureCaller)
+	l, isLocker := db.(Locker)
+	i, isInspector := db.(Inspector)
+	switch [4]bool{isNotifier, isCaller, isLocker, isInspector} {
+	case [4]bool{true, true, true, true}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			hookedCaller
+			Locker
+			Inspector
+		}{h, n, c, l, i}
+	case [4]bool{true, true, true, false}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			hookedCaller
+			Locker
+		}{h, n, c, l}
+	case [4]bool{true, true, false, true}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			hookedCaller
+			Inspector
+		}{h, n, c, i}
+	case [4]bool{true, true, false, false}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			hookedCaller
+		}{h, n, c}
+	case [4]bool{true, false, true, true}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			Locker
+			Inspector
+		}{h, n, l, i}
+	case [4]bool{true, false, true, false}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			Locker
+		}{h, n, l}
+	case [4]bool{true, false, false, true}:
+		return struct {
+			*hookedDatabase
+			Notifier
+			Inspector
+		}{h, n, i}
+	case [4]bool{true, false, false, false}:
+		return struct {
+			*hookedDatabase
+			Notifier
+		}{h, n}
+	case [4]bool{false, true, true, true}:
+		return struct {
+			*hookedDatabase
+			hookedCaller
+			Locker
+			Inspector
+		}{h, c, l, i}
+	case [4]bool{false, true, true, false}:
+		return struct {
+			*hookedDatabase
+			hookedCaller
+			Locker
+		}{h, c, l}
+	case [4]bool{false, true, false, true}:
+		return struct {
+			*hookedDatabase
+			hookedCaller
+			Inspector
+		}{h, c, i}
+	case [4]bool{false, true, false, false}:
+		return struct {
+			*hookedDatabase
+			hookedCaller
+		}{h, c}
+	case [4]bool{false, false, true, true}:
+		return struct {
+			*hookedDatabase
+			Locker
+			Inspector
+		}{h, l, i}
+	case [4]bool{false, false, true, false}:
+		return struct {
+			*hookedDatabase
+			Locker
+		}{h, l}
+	case [4]bool{false, false, false, true}:
+		return struct {
+			*hookedDatabase
+			Inspector
+		}{h, i}
+	}
+	return h
+}
+
+type hookedDatabase struct {
+	db   Database
+	hook QueryHook
+}
+
+// run mide la sentencia ejecutada por `exec` e invoca el hook
+func run[T any](ctx context.Context, hook QueryHook, inTx bool, query string, args int, exec func() (T, error)) (T, error) {
+	start := time.Now()
+	v, err := exec()
+	hook(ctx, QueryInfo{SQL: query, Args: args, Duration: time.Since(start), Err: err, InTx: inTx})
+	return v, err
+}
+
+// hookedRow invoca el hook al escanear la fila, cuando se conoce el resultado de la consulta
+type hookedRow struct {
+	row   Row
+	ctx   context.Context
+	hook  QueryHook
+	info  QueryInfo
+	start time.Time
+}
+
+func (r *hookedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.info.Duration, r.info.Err = time.Since(r.start), err
+	r.hook(r.ctx, r.info)
+	return err
+}
+
+func queryRow(ctx context.Context, hook QueryHook, q Querier, inTx bool, query string, args []any) Row {
+	start := time.Now()
+	return &hookedRow{
+		row:   q.QueryRow(ctx, query, args...),
+		ctx:   ctx,
+		hook:  hook,
+		info:  QueryInfo{SQL: query, Args: len(args), InTx: inTx},
+		start: start,
+	}
+}
+
+func (h *hookedDatabase) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	return run(ctx, h.hook, false, query, len(args), func() (Rows, error) { return h.db.Query(ctx, query, args...) })
+}
+
+func (h *hookedDatabase) QueryRow(ctx context.Context, query string, args ...any) Row {
+	return queryRow(ctx, h.hook, h.db, false, query, args)
+}
+
+func (h *hookedDatabase) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	return run(ctx, h.hook, false, query, len(args), func() (int64, error) { return h.db.Exec(ctx, query, args...) })
+}
+
+func (h *hookedDatabase) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	return run(ctx, h.hook, false, "BULK INSERT "+table, 0, func() (int64, error) {
+		return h.db.BulkInsert(ctx, table, columns, src, opts...)
+	})
+}
+
+func (h *hookedDatabase) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := h.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return withTxHook(tx, h.hook), nil
+}
+
+func (h *hookedDatabase) Close() {
+	h.db.Close()
+}
+
+func (h *hookedDatabase) RawConnection() any {
+	return h.db.RawConnection()
+}
+
+// Ready delega en la conexión diferida (ver database.Ready)
+func (h *hookedDatabase) Ready() error {
+	if r, ok := h.db.(interface{ Ready() error }); ok {
+		return r.Ready()
+	}
+	return nil
+}
+
+// hookedCaller invoca el hook tras cada procedimiento almacenado
+type hookedCaller struct {
+	q    Querier
+	hook QueryHook
+	inTx bool
+}
+
+func (c hookedCaller) CallProcedure(ctx context.Context, name string, params ...Param) (*Results, error) {
+	return run(ctx, c.hook, c.inTx, "CALL "+name, len(params), func() (*Results, error) {
+		return CallProcedure(ctx, c.q, name, params...)
+	})
+}
+
+// withTxHook envuelve la transacción conservando sus capacidades opcionales (ProcedureCaller y Locker)
+func withTxHook(tx Tx, hook QueryHook) Tx {
+	h := &hookedTx{tx: tx, hook: hook}
+	c := hookedCaller{q: tx, hook: hook, inTx: true}
+
+	_, isCaller := tx.(ProcedureCaller)
+	l, isLocker := tx.(Locker)
+	switch {
+	case isCaller && isLocker:
+		return struct {
+			*hookedTx
+			hookedCaller
+			Locker
+		}{h, c, l}
+	case isCaller:
+		return struct {
+			*hookedTx
+			hookedCaller
+		}{h, c}
+	case isLocker:
+		return struct {
+			*hookedTx
+			Locker
+		}{h, l}
+	}
+	return h
+}
+
+// hookedTx es una transacción de una conexión con QueryHook
+type hookedTx struct {
+	tx   Tx
+	hook QueryHook
+}
+
+func (h *hookedTx) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	return run(ctx, h.hook, true, query, len(args), func() (Rows, error) { return h.tx.Query(ctx, query, args...) })
+}
+
+func (h *hookedTx) QueryRow(ctx context.Context, query string, args ...any) Row {
+	return queryRow(ctx, h.hook, h.tx, true, query, args)
+}
+
+func (h *hookedTx) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	return run(ctx, h.hook, true, query, len(args), func() (int64, error) { return h.tx.Exec(ctx, query, args...) })
+}
+
+func (h *hookedTx) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	return run(ctx, h.hook, true, "BULK INSERT "+table, 0, func() (int64, error) {
+		return h.tx.BulkInsert(ctx, table, columns, src, opts...)
+	})
+}
+
+func (h *hookedTx) Commit(ctx context.Context) error {
+	return h.tx.Commit(ctx)
+}
+
+func (h *hookedTx) Rollback(ctx context.Context) error {
+	return h.tx.Rollback(ctx)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/logger"
+)
+
+func TestLogQueries(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(logger.NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	db, err := Open(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite, LogQueries: true})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := logger.WithRequestID(WithDatabase(context.Background(), db), "req-1")
+	if _, err := db.Exec(ctx, "CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if err := RunInTx(ctx, func(ctx context.Context) error {
+		_, err := From(ctx).Exec(ctx, "INSERT INTO t VALUES (?)", 1)
+		return err
+	}); err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	var n int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM t").Scan(&n); err != nil || n != 1 {
+		t.Fatalf("Se esperaba 1 registro, obtuvo: %d (%v)", n, err)
+	}
+	_, _ = db.Exec(ctx, "SELECT * FROM inexistente")
+
+	type entry struct {
+		Level     string `json:"level"`
+		SQL       string `json:"sql"`
+		Args      int    `json:"args"`
+		Tx        bool   `json:"tx"`
+		RequestID string `json:"request_id"`
+		Error     string `json:"error"`
+	}
+	var entries []entry
+	for line := range bytes.Lines(buf.Bytes()) {
+		var e entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			t.Fatalf("Línea de log inválida %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+
+	if len(entries) != 4 {
+		t.Fatalf("Se esperaban 4 sentencias registradas, obtuvo: %d\n%s", len(entries), buf.String())
+	}
+	for _, e := range entries {
+		if e.RequestID != "req-1" {
+			t.Errorf("Se esperaba el request_id en %q, obtuvo: %q", e.SQL, e.RequestID)
+		}
+	}
+	if e := entries[1]; e.SQL != "INSERT INTO t VALUES (?)" || e.Args != 1 || !e.Tx || e.Level != "DEBUG" {
+		t.Errorf("Registro inesperado de la sentencia en la transacción: %+v", e)
+	}
+	if e := entries[2]; e.SQL != "SELECT COUNT(*) FROM t" || e.Tx {
+		t.Errorf("Registro inesperado de QueryRow: %+v", e)
+	}
+	if e := entries[3]; e.Level != "WARN" || e.Error == "" {
+		t.Errorf("Se esperaba la sentencia fallida en nivel WARN, obtuvo: %+v", e)
+	}
+}
+
+// TestWithQueryHookCapabilities valida que el hook no agregue ni quite capacidades opcionales
+func TestWithQueryHookCapabilities(t *testing.T) {
+	db, err := Open(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite, LogQueries: true})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	if _, ok := db.(Notifier); ok {
+		t.Error("SQLite no debería reportar notificaciones con el hook")
+	}
+	if _, ok := db.(Locker); ok {
+		t.Error("SQLite no debería reportar bloqueos con nombre con el hook")
+	}
+	if _, ok := db.(ProcedureCaller); ok {
+		t.Error("SQLite no debería reportar procedimientos almacenados con el hook")
+	}
+	in, ok := db.(Inspector)
+	if !ok {
+		t.Fatal("Se esperaba que SQLite conservara la lectura del esquema con el hook")
+	}
+	if _, err := db.Exec(context.Background(), "CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if tables, err := in.Tables(context.Background(), ""); err != nil || len(tables) != 1 {
+		t.Errorf("Tables() = %v, %v", tables, err)
+	}
+
+	// Una conexión con todas las capacidades (la diferida) las conserva
+	lazy := WithQueryHook(newLazy(func() (Database, error) { return db, nil }), func(context.Context, QueryInfo) {})
+	_, isNotifier := lazy.(Notifier)
+	_, isCaller := lazy.(ProcedureCaller)
+	_, isLocker := lazy.(Locker)
+	_, isInspector := lazy.(Inspector)
+	if !isNotifier || !isCaller || !isLocker || !isInspector {
+		t.Errorf("Se esperaban todas las capacidades, obtuvo Notifier=%v ProcedureCaller=%v Locker=%v Inspector=%v",
+			isNotifier, isCaller, isLocker, isInspector)
+	}
+}
@@ -0,0 +1,281 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTableNotFound indica que la tabla a describir no existe
+var ErrTableNotFound = errors.New("esquema: la tabla no existe")
+
+// Tipos de objeto informados en TableRef.Kind
+const (
+	KindTable = "TABLE"
+	KindView  = "VIEW"
+)
+
+// TableRef identifica una tabla o vista
+type TableRef struct {
+	// Esquema de la tabla (ej. `public`, `dbo`)
+	Schema string `json:"schema"`
+	// Nombre de la tabla
+	Name string `json:"name"`
+	// Tipo de objeto: KindTable o KindView
+	Kind string `json:"kind"`
+}
+
+// Table describe la estructura de una tabla o vista
+type Table struct {
+	TableRef
+	// Columnas en el orden de la tabla
+	Columns []Column `json:"columns"`
+	// Columnas de la llave primaria, en orden; vacío si no tiene
+	PrimaryKey []string `json:"primaryKey"`
+	// Llaves foráneas definidas en la tabla
+	ForeignKeys []ForeignKey `json:"foreignKeys"`
+	// Índices de la tabla, incluido el de la llave primaria
+	Indexes []Index `json:"indexes"`
+}
+
+// Column describe una columna de una tabla
+type Column struct {
+	// Nombre de la columna
+	Name string `json:"name"`
+	// Nombre del tipo en la base de datos, en mayúsculas (igual que ColumnType.DatabaseType)
+	DatabaseType string `json:"databaseType"`
+	// Indica si la columna admite NULL
+	Nullable bool `json:"nullable"`
+	// Expresión del valor por defecto, vacío si no tiene
+	Default string `json:"default,omitempty"`
+	// Largo máximo de los tipos de texto y binarios; -1 si no tiene límite, 0 si no aplica
+	MaxLength int `json:"maxLength,omitempty"`
+	// Precisión y escala de los tipos decimales; 0 si no aplica
+	Precision int `json:"precision,omitempty"`
+	Scale     int `json:"scale,omitempty"`
+	// Posición de la columna, desde 1
+	Position int `json:"position"`
+	// Indica si el valor lo genera la base de datos (IDENTITY, serial)
+	Identity bool `json:"identity"`
+}
+
+// ForeignKey describe una llave foránea
+type ForeignKey struct {
+	// Nombre de la restricción
+	Name string `json:"name"`
+	// Columnas de la tabla, en el mismo orden que RefColumns
+	Columns []string `json:"columns"`
+	// Tabla referenciada
+	RefSchema  string   `json:"refSchema"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+	// Acciones referenciales: NO ACTION, RESTRICT, CASCADE, SET NULL o SET DEFAULT
+	OnDelete string `json:"onDelete"`
+	OnUpdate string `json:"onUpdate"`
+}
+
+// Index describe un índice
+type Index struct {
+	// Nombre del índice
+	Name string `json:"name"`
+	// Columnas de la llave del índice, en orden (sin las columnas incluidas)
+	Columns []string `json:"columns"`
+	// Indica si el índice es único
+	Unique bool `json:"unique"`
+	// Indica si el índice corresponde a la llave primaria
+	Primary bool `json:"primary"`
+}
+
+// Inspector es la capacidad de leer la estructura de la base de datos con los mismos tipos para todos
+// los motores. La implementan Postgres (information_schema y pg_catalog) y MSSQL (vistas sys.*):
+//
+//	if in, ok := database.GetDatabase().(database.Inspector); ok {
+//		tables, err := in.Tables(ctx, "dbo")
+//		...
+//	}
+type Inspector interface {
+	// Tables lista las tablas y vistas del esquema; con `schema` vacío lista todos los esquemas de usuario
+	Tables(ctx context.Context, schema string) ([]TableRef, error)
+	// DescribeTable devuelve la estructura de la tabla; con `schema` vacío usa el esquema por defecto de
+	// la conexión. Devuelve ErrTableNotFound si no existe
+	DescribeTable(ctx context.Context, schema, table string) (*Table, error)
+}
+
+// DescribeSchema describe todas las tablas y vistas del esquema
+func DescribeSchema(ctx context.Context, in Inspector, schema string) ([]Table, error) {
+	refs, err := in.Tables(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]Table, 0, len(refs))
+	for _, ref := range refs {
+		t, err := in.DescribeTable(ctx, ref.Schema, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, *t)
+	}
+	return tables, nil
+}
+
+// schemaQueries contiene las consultas de catálogo de un motor. Todas reciben el esquema y la tabla
+// como primer y segundo parámetro, salvo tables que solo recibe el esquema
+type schemaQueries struct {
+	// tables: esquema, nombre, tipo
+	tables string
+	// table: esquema, nombre, tipo de una tabla puntual (resuelve el esquema por defecto)
+	table string
+	// columns: nombre, tipo, nullable, default, largo, precisión, escala, posición, identity
+	columns string
+	// indexes: índice, único, primario, columna; ordenado por índice y posición
+	indexes string
+	// foreignKeys: restricción, esquema ref, tabla ref, columna, columna ref, on delete, on update;
+	// ordenado por restricción y posición
+	foreignKeys string
+}
+
+func listTables(ctx context.Context, q Querier, sq schemaQueries, schema string) ([]TableRef, error) {
+	rows, err := q.Query(ctx, sq.tables, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []TableRef
+	for rows.Next() {
+		var ref TableRef
+		if err := rows.Scan(&ref.Schema, &ref.Name, &ref.Kind); err != nil {
+			return nil, err
+		}
+		ref.Kind = tableKind(ref.Kind)
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+func describeTable(ctx context.Context, q Querier, sq schemaQueries, schema, table string) (*Table, error) {
+	t := &Table{}
+	err := q.QueryRow(ctx, sq.table, schema, table).Scan(&t.Schema, &t.Name, &t.Kind)
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, ErrTableNotFound
+		}
+		return nil, err
+	}
+	t.Kind = tableKind(t.Kind)
+
+	if t.Columns, err = readColumns(ctx, q, sq.columns, t.Schema, t.Name); err != nil {
+		return nil, err
+	}
+	if t.Indexes, err = readIndexes(ctx, q, sq.indexes, t.Schema, t.Name); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = readForeignKeys(ctx, q, sq.foreignKeys, t.Schema, t.Name); err != nil {
+		return nil, err
+	}
+
+	t.PrimaryKey = []string{}
+	for _, idx := range t.Indexes {
+		if idx.Primary {
+			t.PrimaryKey = idx.Columns
+		}
+	}
+
+	return t, nil
+}
+
+func readColumns(ctx context.Context, q Querier, query, schema, table string) ([]Column, error) {
+	rows, err := q.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := []Column{}
+	for rows.Next() {
+		var c Column
+		var def sql.NullString
+		var length, precision, scale sql.NullInt64
+		if err := rows.Scan(&c.Name, &c.DatabaseType, &c.Nullable, &def, &length, &precision, &scale, &c.Position, &c.Identity); err != nil {
+			return nil, err
+		}
+		c.Default = def.String
+		c.MaxLength = int(length.Int64)
+		c.Precision = int(precision.Int64)
+		c.Scale = int(scale.Int64)
+		cols = append(cols, c)
+	}
+	return cols, rows.Err()
+}
+
+func readIndexes(ctx context.Context, q Querier, query, schema, table string) ([]Index, error) {
+	rows, err := q.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	idxs := []Index{}
+	for rows.Next() {
+		var idx Index
+		var column string
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.Primary, &column); err != nil {
+			return nil, err
+		}
+		if n := len(idxs); n > 0 && idxs[n-1].Name == idx.Name {
+			idxs[n-1].Columns = append(idxs[n-1].Columns, column)
+			continue
+		}
+		idx.Columns = []string{column}
+		idxs = append(idxs, idx)
+	}
+	return idxs, rows.Err()
+}
+
+func readForeignKeys(ctx context.Context, q Querier, query, schema, table string) ([]ForeignKey, error) {
+	rows, err := q.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fks := []ForeignKey{}
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &fk.RefSchema, &fk.RefTable, &column, &refColumn, &fk.OnDelete, &fk.OnUpdate); err != nil {
+			return nil, err
+		}
+		if n := len(fks); n > 0 && fks[n-1].Name == fk.Name {
+			fks[n-1].Columns = append(fks[n-1].Columns, column)
+			fks[n-1].RefColumns = append(fks[n-1].RefColumns, refColumn)
+			continue
+		}
+		fk.Columns, fk.RefColumns = []string{column}, []string{refColumn}
+		fk.OnDelete, fk.OnUpdate = referentialAction(fk.OnDelete), referentialAction(fk.OnUpdate)
+		fks = append(fks, fk)
+	}
+	return fks, rows.Err()
+}
+
+// tableKind normaliza el tipo informado por information_schema (`BASE TABLE`, `VIEW`)
+func tableKind(kind string) string {
+	if strings.EqualFold(kind, "VIEW") {
+		return KindView
+	}
+	return KindTable
+}
+
+// referentialAction normaliza las acciones referenciales (SQL Server informa `SET_NULL`)
+func referentialAction(action string) string {
+	return strings.ReplaceAll(strings.ToUpper(action), "_", " ")
+}
+
+// IsNoRows indica si el error corresponde a una consulta sin filas, tanto de database/sql como de pgx
+func IsNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows)
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+func TestSQLite_DescribeTable(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	for _, ddl := range []string{
+		"CREATE TABLE clientes (id INTEGER PRIMARY KEY, nombre TEXT NOT NULL, email TEXT)",
+		`CREATE TABLE pedidos (
+			id INTEGER PRIMARY KEY,
+			cliente_id INTEGER NOT NULL REFERENCES clientes (id) ON DELETE CASCADE,
+			total NUMERIC DEFAULT 0
+		)`,
+		"CREATE UNIQUE INDEX ux_clientes_email ON clientes (email)",
+		"CREATE VIEW v_clientes AS SELECT id, nombre FROM clientes",
+	} {
+		if _, err := db.Exec(ctx, ddl); err != nil {
+			t.Fatalf("Error al crear el esquema: %v", err)
+		}
+	}
+
+	in := db.(Inspector)
+	refs, err := in.Tables(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TableRef{{"main", "clientes", KindTable}, {"main", "pedidos", KindTable}, {"main", "v_clientes", KindView}}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("Tables() = %v", refs)
+	}
+
+	pedidos, err := in.DescribeTable(ctx, "", "pedidos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pedidos.PrimaryKey, []string{"id"}) {
+		t.Errorf("PrimaryKey = %v", pedidos.PrimaryKey)
+	}
+	if len(pedidos.Columns) != 3 {
+		t.Fatalf("Columns = %+v", pedidos.Columns)
+	}
+	id, cliente, total := pedidos.Columns[0], pedidos.Columns[1], pedidos.Columns[2]
+	if !id.Identity || id.Nullable || id.Position != 1 {
+		t.Errorf("id = %+v", id)
+	}
+	if cliente.DatabaseType != "INTEGER" || cliente.Nullable || cliente.Identity {
+		t.Errorf("cliente_id = %+v", cliente)
+	}
+	if !total.Nullable || total.Default != "0" {
+		t.Errorf("total = %+v", total)
+	}
+
+	wantFK := []ForeignKey{{
+		Name: "fk_pedidos_0", Columns: []string{"cliente_id"},
+		RefSchema: "main", RefTable: "clientes", RefColumns: []string{"id"},
+		OnDelete: "CASCADE", OnUpdate: "NO ACTION",
+	}}
+	if !reflect.DeepEqual(pedidos.ForeignKeys, wantFK) {
+		t.Errorf("ForeignKeys = %+v", pedidos.ForeignKeys)
+	}
+
+	clientes, err := in.DescribeTable(ctx, "main", "clientes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIdx := []Index{
+		{Name: "pk_clientes", Columns: []string{"id"}, Unique: true, Primary: true},
+		{Name: "ux_clientes_email", Columns: []string{"email"}, Unique: true},
+	}
+	if !reflect.DeepEqual(clientes.Indexes, wantIdx) {
+		t.Errorf("Indexes = %+v", clientes.Indexes)
+	}
+
+	if _, err := in.DescribeTable(ctx, "", "no_existe"); !errors.Is(err, ErrTableNotFound) {
+		t.Errorf("DescribeTable() = %v; se esperaba ErrTableNotFound", err)
+	}
+
+	all, err := DescribeSchema(ctx, in, "main")
+	if err != nil || len(all) != 3 || all[2].Kind != KindView {
+		t.Errorf("DescribeSchema() = %d tablas, %v", len(all), err)
+	}
+}
+
+func TestReferentialAction(t *testing.T) {
+	for in, want := range map[string]string{"SET_NULL": "SET NULL", "no_action": "NO ACTION", "CASCADE": "CASCADE"} {
+		if got := referentialAction(in); got != want {
+			t.Errorf("referentialAction(%q) = %q", in, got)
+		}
+	}
+}
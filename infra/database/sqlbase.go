@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
@@ -37,6 +38,7 @@ func newConnection(driverName, dataSourceName string) (*sql.DB, error) {
 	defer cancel()
 	err = cnx.PingContext(ctx)
 	if err != nil {
+		cnx.Close()
 		return nil, err
 	}
 
@@ -78,6 +80,27 @@ func (db *sqlBase) BeginTx(ctx context.Context) (Tx, error) {
 	return &sqlBaseTx{tx}, nil
 }
 
+// BulkInsert carga las filas en lotes de INSERT de varias filas dentro de una transacción
+func (db *sqlBase) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	defer closeSource(src)
+	if err := validateBulkTarget(table, columns); err != nil {
+		return 0, err
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	n, err := insertBatches(ctx, tx, table, columns, src, newBulkOptions(opts))
+	if err != nil {
+		return 0, err
+	}
+
+	return n, tx.Commit()
+}
+
 func (db *sqlBase) RawConnection() any {
 	return db.DB
 }
@@ -96,6 +119,22 @@ func (r *sqlBaseRows) Close() {
 	r.Rows.Close()
 }
 
+func (r *sqlBaseRows) ColumnTypes() ([]ColumnType, error) {
+	cts, err := r.Rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]ColumnType, len(cts))
+	for i, ct := range cts {
+		types[i] = ColumnType{Name: ct.Name(), DatabaseType: strings.ToUpper(ct.DatabaseTypeName()), ScanType: ct.ScanType()}
+		if types[i].ScanType == nil {
+			types[i].ScanType = anyType
+		}
+	}
+	return types, nil
+}
+
 func (r *sqlBaseRow) Scan(dest ...any) error {
 	return r.Row.Scan(dest...)
 }
@@ -123,6 +162,14 @@ func (tx *sqlBaseTx) Exec(ctx context.Context, query string, args ...any) (int64
 	return n, nil
 }
 
+func (tx *sqlBaseTx) BulkInsert(ctx context.Context, table string, columns []string, src RowSource, opts ...BulkOption) (int64, error) {
+	defer closeSource(src)
+	if err := validateBulkTarget(table, columns); err != nil {
+		return 0, err
+	}
+	return insertBatches(ctx, tx.Tx, table, columns, src, newBulkOptions(opts))
+}
+
 func (tx *sqlBaseTx) Commit(ctx context.Context) error {
 	return tx.Tx.Commit()
 }
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/wfrscltech/vulcano/config"
+	_ "modernc.org/sqlite"
+)
+
+// Nombre reservado para una base de datos SQLite en memoria
+const sqliteMemory = ":memory:"
+
+// Adaptador de SQLite siguiendo la especificación de la base de datos y la liberia modernc.org/sqlite (Go puro, sin CGO)
+type SQLite struct {
+	*sqlBase
+}
+
+func newSQLiteCnx(dcfg config.DatabaseConfig) (Database, error) {
+	cnx, err := newConnection("sqlite", sqlitedsn(dcfg))
+	if err != nil {
+		return nil, err
+	}
+
+	// Cada conexión en memoria es una base de datos distinta, por lo que se limita el pool a una
+	// única conexión que nunca se cierra para que todas las operaciones vean los mismos datos
+	if dcfg.Name == sqliteMemory {
+		cnx.SetMaxOpenConns(1)
+		cnx.SetMaxIdleConns(1)
+		cnx.SetConnMaxLifetime(0)
+		cnx.SetConnMaxIdleTime(0)
+	}
+
+	return &SQLite{sqlBase: &sqlBase{DB: cnx}}, nil
+}
+
+func sqlitedsn(dcfg config.DatabaseConfig) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+
+	if dcfg.Name == sqliteMemory {
+		return fmt.Sprintf("file::memory:?%s", params.Encode())
+	}
+
+	// La ruta se escapa para que un `?` o un `#` del nombre no se confunda con los parámetros del URI
+	params.Add("_pragma", "journal_mode(WAL)")
+	return fmt.Sprintf("file:%s?%s", (&url.URL{Path: dcfg.Name}).EscapedPath(), params.Encode())
+}
@@ -0,0 +1,51 @@
+package database
+
+import "context"
+
+// Consultas de catálogo de SQLite basadas en las funciones pragma_*. El único esquema es `main`; la
+// llave primaria INTEGER es un alias de rowid y no tiene índice propio, por lo que se informa a partir de
+// pragma_table_info. Las columnas de la llave primaria se informan como no anulables aunque SQLite, por
+// compatibilidad, admita NULL en ellas
+var sqliteSchemaQueries = schemaQueries{
+	tables: `SELECT 'main', name, CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END
+FROM sqlite_master
+WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' AND ?1 IN ('', 'main')
+ORDER BY name`,
+
+	table: `SELECT 'main', name, CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END
+FROM sqlite_master
+WHERE type IN ('table', 'view') AND ?1 IN ('', 'main') AND name = ?2`,
+
+	columns: `SELECT name, UPPER(type), "notnull" = 0 AND pk = 0, dflt_value,
+  NULL, NULL, NULL, cid + 1,
+  pk > 0 AND UPPER(type) = 'INTEGER' AND (SELECT COUNT(*) FROM pragma_table_info(?2) WHERE pk > 0) = 1
+FROM pragma_table_info(?2)
+WHERE ?1 IN ('', 'main')
+ORDER BY cid`,
+
+	indexes: `SELECT idx, uniq, prim, col FROM (
+  SELECT il.name AS idx, il."unique" AS uniq, il.origin = 'pk' AS prim, ii.name AS col, ii.seqno AS pos
+  FROM pragma_index_list(?2) il JOIN pragma_index_info(il.name) ii
+  WHERE ?1 IN ('', 'main')
+  UNION ALL
+  SELECT 'pk_' || ?2, 1, 1, name, pk
+  FROM pragma_table_info(?2)
+  WHERE pk > 0 AND NOT EXISTS (SELECT 1 FROM pragma_index_list(?2) WHERE origin = 'pk')
+)
+ORDER BY idx, pos`,
+
+	foreignKeys: `SELECT 'fk_' || ?2 || '_' || id, 'main', "table", "from", COALESCE("to", ''), on_delete, on_update
+FROM pragma_foreign_key_list(?2)
+WHERE ?1 IN ('', 'main')
+ORDER BY id, seq`,
+}
+
+// Tables lista las tablas y vistas del esquema `main`
+func (db *SQLite) Tables(ctx context.Context, schema string) ([]TableRef, error) {
+	return listTables(ctx, db, sqliteSchemaQueries, schema)
+}
+
+// DescribeTable describe la tabla con las funciones pragma de SQLite
+func (db *SQLite) DescribeTable(ctx context.Context, schema, table string) (*Table, error) {
+	return describeTable(ctx, db, sqliteSchemaQueries, schema, table)
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// TestSQLite_Memory valida que todas las operaciones en memoria compartan la misma base de datos
+func TestSQLite_Memory(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE clientes (id INTEGER PRIMARY KEY, nombre TEXT NOT NULL)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("Error al iniciar transacción: %v", err)
+	}
+	n, err := tx.Exec(ctx, "INSERT INTO clientes (nombre) VALUES (?), (?)", "Ana", "Luis")
+	if err != nil {
+		t.Fatalf("Error al insertar: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Se esperaban 2 filas afectadas, obtuvo: %d", n)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Error al confirmar transacción: %v", err)
+	}
+
+	var total int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM clientes").Scan(&total); err != nil {
+		t.Fatalf("Error al contar registros: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Se esperaban 2 registros, obtuvo: %d", total)
+	}
+}
+
+// TestSQLite_File valida el modo archivo y que las claves foráneas estén habilitadas
+func TestSQLite_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vulcano.db")
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: path, Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	var fk int
+	if err := db.QueryRow(ctx, "PRAGMA foreign_keys").Scan(&fk); err != nil {
+		t.Fatalf("Error al consultar pragma: %v", err)
+	}
+	if fk != 1 {
+		t.Errorf("Se esperaba foreign_keys=1, obtuvo: %d", fk)
+	}
+}
+
+// TestSQLite_FileEscaped valida que la ruta del archivo se escape en el URI
+func TestSQLite_FileEscaped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ventas?2024#1.db")
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: path, Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(context.Background(), "CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatalf("Error al crear la tabla: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Se esperaba el archivo %s, obtuvo: %v", path, err)
+	}
+}
+
+// TestSQLite_ColumnTypes valida la descripción de columnas y el cierre de registros en iteradores
+func TestSQLite_ColumnTypes(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE productos (id INTEGER, nombre TEXT); INSERT INTO productos VALUES (1, 'A'), (2, 'B')"); err != nil {
+		t.Fatalf("Error al preparar datos: %v", err)
+	}
+
+	rows, err := db.Query(ctx, "SELECT id, nombre FROM productos ORDER BY id")
+	if err != nil {
+		t.Fatalf("Error al consultar: %v", err)
+	}
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("Error al obtener tipos de columnas: %v", err)
+	}
+	if len(cts) != 2 || cts[0].Name != "id" || cts[0].DatabaseType != "INTEGER" || cts[1].DatabaseType != "TEXT" {
+		t.Errorf("Tipos de columnas inesperados: %+v", cts)
+	}
+
+	// Interrumpir el recorrido debe cerrar los registros y liberar la única conexión en memoria
+	for range All(rows, ScanValues(len(cts))) {
+		break
+	}
+	if _, err := db.Exec(ctx, "DELETE FROM productos"); err != nil {
+		t.Errorf("Se esperaba que la conexión estuviera libre tras interrumpir el recorrido: %v", err)
+	}
+}
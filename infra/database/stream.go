@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"iter"
+	"reflect"
+)
+
+// anyType es el tipo de escaneo por defecto cuando el driver no informa uno
+var anyType = reflect.TypeFor[any]()
+
+// ScanFunc convierte el registro actual en un valor de tipo T
+type ScanFunc[T any] func(row Row) (T, error)
+
+// All recorre `rows` como un iterador, convirtiendo cada registro con `scan`. Los registros siempre se
+// cierran, aun si el consumidor interrumpe el recorrido, y los errores a mitad del conjunto se entregan
+// como último elemento:
+//
+//	for c, err := range database.All(rows, scanCliente) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func All[T any](rows Rows, scan ScanFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer rows.Close()
+
+		for rows.Next() {
+			v, err := scan(rows)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Stream ejecuta la consulta y recorre sus registros con All. El error de la consulta, si lo hay, se
+// entrega como único elemento del iterador
+func Stream[T any](ctx context.Context, q Querier, scan ScanFunc[T], query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		for v, err := range All(rows, scan) {
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// Collect consume el iterador y devuelve todos sus valores, o el primer error encontrado
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var values []T
+	for v, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// ScanValue es un ScanFunc para consultas de una sola columna
+func ScanValue[T any](row Row) (T, error) {
+	var v T
+	err := row.Scan(&v)
+	return v, err
+}
+
+// ScanValues es un ScanFunc que devuelve los valores de todas las columnas del registro, en el tipo que
+// entrega el driver
+func ScanValues(columns int) ScanFunc[[]any] {
+	return func(row Row) ([]any, error) {
+		values := make([]any, columns)
+		dest := make([]any, columns)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := row.Scan(dest...); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+}
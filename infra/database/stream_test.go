@@ -0,0 +1,73 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/databasetest"
+)
+
+type cliente struct {
+	ID     int
+	Nombre string
+}
+
+func scanCliente(row database.Row) (cliente, error) {
+	var c cliente
+	err := row.Scan(&c.ID, &c.Nombre)
+	return c, err
+}
+
+// TestStream_MidStreamError valida que una falla a mitad del conjunto no se confunda con el fin de los registros
+func TestStream_MidStreamError(t *testing.T) {
+	db := databasetest.New(t)
+	lost := errors.New("conexión perdida")
+	db.ExpectQuery("SELECT id, nombre FROM clientes").
+		WillReturnRows(databasetest.NewRows("id", "nombre").AddRow(1, "Ana").AddRow(2, "Luis").RowError(1, lost))
+
+	var got []cliente
+	var gotErr error
+	for c, err := range database.Stream(context.Background(), db, scanCliente, "SELECT id, nombre FROM clientes") {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, c)
+	}
+
+	if len(got) != 1 || got[0].Nombre != "Ana" {
+		t.Errorf("Se esperaba solo el primer registro, obtuvo: %+v", got)
+	}
+	if !errors.Is(gotErr, lost) {
+		t.Errorf("Se esperaba el error a mitad del conjunto, obtuvo: %v", gotErr)
+	}
+}
+
+// TestStream_QueryError valida que el error de la consulta se entregue como único elemento
+func TestStream_QueryError(t *testing.T) {
+	db := databasetest.New(t)
+	boom := errors.New("sintaxis inválida")
+	db.ExpectQuery("SELECT nombre FROM clientes").WillReturnError(boom)
+
+	values, err := database.Collect(database.Stream(context.Background(), db, database.ScanValue[string], "SELECT nombre FROM clientes"))
+	if !errors.Is(err, boom) || values != nil {
+		t.Errorf("Se esperaba el error de la consulta, obtuvo: %v, %v", values, err)
+	}
+}
+
+// TestCollect valida la lectura completa de los registros
+func TestCollect(t *testing.T) {
+	db := databasetest.New(t)
+	db.ExpectQuery("SELECT id, nombre FROM clientes").
+		WillReturnRows(databasetest.NewRows("id", "nombre").AddRow(1, "Ana").AddRow(2, "Luis"))
+
+	got, err := database.Collect(database.Stream(context.Background(), db, scanCliente, "SELECT id, nombre FROM clientes"))
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if len(got) != 2 || got[1].ID != 2 {
+		t.Errorf("Registros inesperados: %+v", got)
+	}
+}
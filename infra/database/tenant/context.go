@@ -0,0 +1,17 @@
+package tenant
+
+import "context"
+
+// idKey es la llave del tenant de la petición en el contexto
+type idKey struct{}
+
+// WithID devuelve un contexto que transporta el identificador del tenant
+func WithID(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, idKey{}, tenant)
+}
+
+// ID devuelve el identificador del tenant del contexto, si existe
+func ID(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(idKey{}).(string)
+	return tenant, ok
+}
@@ -0,0 +1,255 @@
+// Package tenant mantiene las conexiones de cada tenant (cliente) de un servicio multi-tenant. Las
+// conexiones se abren la primera vez que se usan y se cierran cuando quedan inactivas, de modo que un
+// servicio con cientos de clientes solo mantiene los pools de los que están trabajando.
+//
+// Admite una base de datos por tenant en el mismo servidor (Databases) o, en PostgreSQL, un esquema por
+// tenant en la misma base (Schemas).
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Tiempo de inactividad por defecto tras el cual se cierra la conexión de un tenant
+const DefaultIdleTimeout = 30 * time.Minute
+
+// ErrUnknownTenant indica que el tenant no está registrado
+var ErrUnknownTenant = errors.New("tenant desconocido")
+
+// schemaRe valida los nombres de esquema, que se envían como parámetro de la sesión
+var schemaRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// OpenFunc abre la conexión de un tenant; devuelve ErrUnknownTenant si el tenant no existe
+type OpenFunc func(tenant string) (database.Database, error)
+
+// Databases abre una base de datos por tenant: usa la configuración `base` reemplazando el nombre de la
+// base por el asociado al tenant en `names`
+func Databases(base config.DatabaseConfig, names map[string]string) OpenFunc {
+	return func(tenant string) (database.Database, error) {
+		name, ok := names[tenant]
+		if !ok {
+			return nil, ErrUnknownTenant
+		}
+		dcfg := base
+		dcfg.Name = name
+		return database.Open(dcfg)
+	}
+}
+
+// Schemas abre una conexión por tenant sobre la misma base de PostgreSQL, con el `search_path` de sus
+// sesiones fijado en el esquema asociado al tenant en `schemas`
+func Schemas(base config.DatabaseConfig, schemas map[string]string) (OpenFunc, error) {
+	if base.Typo != config.DatabaseTypePostgres {
+		return nil, fmt.Errorf("los tenants por esquema solo se admiten en postgres, no en %s", base.Typo)
+	}
+	for tenant, schema := range schemas {
+		if !schemaRe.MatchString(schema) {
+			return nil, fmt.Errorf("el esquema `%s` del tenant `%s` no es válido", schema, tenant)
+		}
+	}
+
+	return func(tenant string) (database.Database, error) {
+		schema, ok := schemas[tenant]
+		if !ok {
+			return nil, ErrUnknownTenant
+		}
+		dcfg := base
+		dcfg.Schema = schema
+		return database.Open(dcfg)
+	}, nil
+}
+
+// Option configura el registro de tenants
+type Option func(*Registry)
+
+// WithIdleTimeout cambia el tiempo de inactividad tras el cual se cierra la conexión de un tenant; con 0
+// las conexiones no se cierran hasta Close
+func WithIdleTimeout(d time.Duration) Option {
+	return func(r *Registry) {
+		r.idleTimeout = d
+	}
+}
+
+// Registry mantiene las conexiones abiertas de los tenants
+type Registry struct {
+	open        OpenFunc
+	idleTimeout time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	closed  bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// entry es la conexión de un tenant. `ready` se cierra cuando termina la apertura, para que las peticiones
+// concurrentes del mismo tenant la esperen en lugar de abrir otra
+type entry struct {
+	db    database.Database
+	err   error
+	ready chan struct{}
+
+	refs     int
+	lastUsed time.Time
+}
+
+// NewRegistry crea el registro de tenants que abre las conexiones con `open`
+func NewRegistry(open OpenFunc, opts ...Option) *Registry {
+	r := &Registry{
+		open:        open,
+		idleTimeout: DefaultIdleTimeout,
+		entries:     map[string]*entry{},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.idleTimeout > 0 {
+		go r.evictLoop()
+	} else {
+		close(r.done)
+	}
+	return r
+}
+
+// Get devuelve la conexión del tenant, abriéndola si es necesario. La conexión no se cierra por
+// inactividad hasta invocar `release`, que debe llamarse al terminar de usarla (ej. al final de la petición)
+func (r *Registry) Get(ctx context.Context, tenant string) (database.Database, func(), error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, nil, errors.New("el registro de tenants está cerrado")
+	}
+
+	e, ok := r.entries[tenant]
+	if !ok {
+		e = &entry{ready: make(chan struct{})}
+		r.entries[tenant] = e
+		go r.load(tenant, e)
+	}
+	e.refs++
+	r.mu.Unlock()
+
+	select {
+	case <-e.ready:
+	case <-ctx.Done():
+		r.release(e)
+		return nil, nil, ctx.Err()
+	}
+
+	if e.err != nil {
+		r.release(e)
+		return nil, nil, e.err
+	}
+
+	var once sync.Once
+	return e.db, func() { once.Do(func() { r.release(e) }) }, nil
+}
+
+// load abre la conexión del tenant. Si falla se descarta la entrada para que el próximo uso lo reintente
+func (r *Registry) load(tenant string, e *entry) {
+	db, err := r.open(tenant)
+
+	r.mu.Lock()
+	e.db, e.err, e.lastUsed = db, err, time.Now()
+	if err != nil {
+		delete(r.entries, tenant)
+	}
+	r.mu.Unlock()
+
+	close(e.ready)
+}
+
+func (r *Registry) release(e *entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.refs--
+	e.lastUsed = time.Now()
+}
+
+// Tenants devuelve los tenants con conexión abierta
+func (r *Registry) Tenants() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var tenants []string
+	for t, e := range r.entries {
+		if e.db != nil {
+			tenants = append(tenants, t)
+		}
+	}
+	slices.Sort(tenants)
+	return tenants
+}
+
+// Close cierra todas las conexiones de los tenants
+func (r *Registry) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.stop)
+	r.mu.Unlock()
+	<-r.done
+
+	r.mu.Lock()
+	entries := r.entries
+	r.entries = map[string]*entry{}
+	r.mu.Unlock()
+
+	for _, e := range entries {
+		<-e.ready
+		if e.db != nil {
+			e.db.Close()
+		}
+	}
+}
+
+func (r *Registry) evictLoop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(max(r.idleTimeout/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.evict(time.Now().Add(-r.idleTimeout))
+		}
+	}
+}
+
+// evict cierra las conexiones sin uso desde antes de `before`
+func (r *Registry) evict(before time.Time) {
+	r.mu.Lock()
+	var idle []database.Database
+	for t, e := range r.entries {
+		if e.db != nil && e.refs == 0 && e.lastUsed.Before(before) {
+			idle = append(idle, e.db)
+			delete(r.entries, t)
+			slog.Info("Conexión del tenant cerrada por inactividad", slog.String("tenant", t))
+		}
+	}
+	r.mu.Unlock()
+
+	for _, db := range idle {
+		db.Close()
+	}
+}
@@ -0,0 +1,141 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// sqliteTenants registra un archivo SQLite por tenant en un directorio temporal
+func sqliteTenants(t *testing.T, tenants ...string) OpenFunc {
+	t.Helper()
+
+	dir := t.TempDir()
+	names := map[string]string{}
+	for _, tn := range tenants {
+		names[tn] = filepath.Join(dir, tn+".db")
+	}
+	return Databases(config.DatabaseConfig{Typo: config.DatabaseTypeSqlite}, names)
+}
+
+func TestRegistryGet(t *testing.T) {
+	reg := NewRegistry(sqliteTenants(t, "acme", "globex"), WithIdleTimeout(0))
+	defer reg.Close()
+
+	ctx := context.Background()
+	for _, tn := range []string{"acme", "globex"} {
+		db, release, err := reg.Get(ctx, tn)
+		if err != nil {
+			t.Fatalf("Get(%s) = %v", tn, err)
+		}
+		if _, err := db.Exec(ctx, "CREATE TABLE cliente (nombre TEXT)"); err != nil {
+			t.Fatalf("Error al crear tabla en %s: %v", tn, err)
+		}
+		if _, err := db.Exec(ctx, "INSERT INTO cliente VALUES (?)", tn); err != nil {
+			t.Fatalf("Error al insertar en %s: %v", tn, err)
+		}
+		release()
+	}
+
+	// Cada tenant ve solo sus datos y reutiliza la conexión abierta
+	db, release, err := reg.Get(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Get(acme) = %v", err)
+	}
+	defer release()
+	var nombre string
+	if err := db.QueryRow(ctx, "SELECT nombre FROM cliente").Scan(&nombre); err != nil || nombre != "acme" {
+		t.Errorf("Se esperaba el cliente acme, obtuvo %q (%v)", nombre, err)
+	}
+	if got := reg.Tenants(); !slices.Equal(got, []string{"acme", "globex"}) {
+		t.Errorf("Tenants() = %v", got)
+	}
+
+	if _, _, err := reg.Get(ctx, "initech"); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("Get(initech) = %v; se esperaba ErrUnknownTenant", err)
+	}
+}
+
+func TestRegistryConcurrentOpen(t *testing.T) {
+	var mu sync.Mutex
+	opened := 0
+	open := sqliteTenants(t, "acme")
+	reg := NewRegistry(func(tn string) (database.Database, error) {
+		mu.Lock()
+		opened++
+		mu.Unlock()
+		return open(tn)
+	}, WithIdleTimeout(0))
+	defer reg.Close()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, release, err := reg.Get(context.Background(), "acme")
+			if err != nil {
+				t.Errorf("Get(acme) = %v", err)
+				return
+			}
+			release()
+		}()
+	}
+	wg.Wait()
+
+	if opened != 1 {
+		t.Errorf("Se esperaba abrir la conexión una sola vez, se abrió %d veces", opened)
+	}
+}
+
+func TestRegistryEvict(t *testing.T) {
+	reg := NewRegistry(sqliteTenants(t, "acme", "globex"), WithIdleTimeout(time.Hour))
+	defer reg.Close()
+
+	ctx := context.Background()
+	_, releaseAcme, err := reg.Get(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Get(acme) = %v", err)
+	}
+	_, releaseGlobex, err := reg.Get(ctx, "globex")
+	if err != nil {
+		t.Fatalf("Get(globex) = %v", err)
+	}
+	releaseGlobex()
+
+	// La conexión en uso no se cierra aunque supere el tiempo de inactividad
+	reg.evict(time.Now().Add(time.Minute))
+	if got := reg.Tenants(); !slices.Equal(got, []string{"acme"}) {
+		t.Errorf("Tenants() = %v; se esperaba solo acme", got)
+	}
+
+	releaseAcme()
+	releaseAcme()
+	reg.evict(time.Now().Add(time.Minute))
+	if got := reg.Tenants(); len(got) != 0 {
+		t.Errorf("Tenants() = %v; se esperaba ninguno", got)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	if _, err := Schemas(config.DatabaseConfig{Typo: config.DatabaseTypeMssql}, nil); err == nil {
+		t.Error("Se esperaba error con tenants por esquema en SQL Server")
+	}
+	if _, err := Schemas(config.DatabaseConfig{Typo: config.DatabaseTypePostgres}, map[string]string{"acme": "acme; DROP"}); err == nil {
+		t.Error("Se esperaba error con un esquema inválido")
+	}
+	open, err := Schemas(config.DatabaseConfig{Typo: config.DatabaseTypePostgres}, map[string]string{"acme": "acme"})
+	if err != nil {
+		t.Fatalf("Schemas() = %v", err)
+	}
+	if _, err := open("globex"); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("open(globex) = %v; se esperaba ErrUnknownTenant", err)
+	}
+}
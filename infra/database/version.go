@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+// ErrVersionConflict indica que el registro cambió (o dejó de existir) desde que se leyó su versión
+var ErrVersionConflict = errors.New("la versión del registro no coincide")
+
+// VersionKind define cómo se obtiene la versión de un registro para el control de concurrencia optimista
+type VersionKind int
+
+const (
+	// Columna entera que la actualización incrementa en uno
+	VersionInteger VersionKind = iota
+	// Columna `rowversion` de SQL Server, que el motor cambia en cada actualización
+	VersionRowversion
+	// Columna de sistema `xmin` de PostgreSQL, que cambia en cada actualización
+	VersionXmin
+)
+
+// Version describe la columna de versión de una tabla. Todas las variantes se leen como un entero de
+// 64 bits, que se usa como ETag y se compara en las actualizaciones
+type Version struct {
+	Kind VersionKind
+	// Nombre de la columna; en VersionXmin se puede omitir o indicar el alias de la tabla (ej. `c.xmin`)
+	Column string
+}
+
+func (v Version) column() string {
+	if v.Column == "" && v.Kind == VersionXmin {
+		return "xmin"
+	}
+	return v.Column
+}
+
+// Expr devuelve la expresión SQL que lee la versión como entero, para usar en el SELECT y en la condición
+// de las actualizaciones
+func (v Version) Expr() string {
+	switch v.Kind {
+	case VersionRowversion:
+		return fmt.Sprintf("CAST(%s AS BIGINT)", v.column())
+	case VersionXmin:
+		return v.column() + "::text::bigint"
+	}
+	return v.column()
+}
+
+// Match devuelve la condición que compara la versión con el marcador `n` del dialecto (ej. `xmin::text::bigint = $3`)
+func (v Version) Match(typo string, n int) string {
+	return fmt.Sprintf("%s = %s", v.Expr(), Placeholder(typo, n))
+}
+
+// Increment devuelve la asignación del SET que avanza la versión (ej. `version = version + 1`). Es una
+// cadena vacía para las versiones que mantiene el motor
+func (v Version) Increment() string {
+	if v.Kind != VersionInteger {
+		return ""
+	}
+	return fmt.Sprintf("%s = %s + 1", v.Column, v.Column)
+}
+
+// Validate comprueba que la columna sea un identificador válido y que el tipo de versión corresponda al motor
+func (v Version) Validate(typo string) error {
+	if !identifierRe.MatchString(v.column()) {
+		return fmt.Errorf("el nombre de columna de versión `%s` no es válido", v.column())
+	}
+	switch {
+	case v.Kind == VersionRowversion && typo != config.DatabaseTypeMssql:
+		return errors.New("la versión rowversion solo está disponible en SQL Server")
+	case v.Kind == VersionXmin && typo != config.DatabaseTypePostgres:
+		return errors.New("la versión xmin solo está disponible en PostgreSQL")
+	}
+	return nil
+}
+
+// ExecVersioned ejecuta una actualización o eliminación condicionada por la versión (ver Version.Match). Si
+// no afecta registros devuelve el error de VersionConflict: el registro fue modificado por otro usuario o
+// ya no existe
+func ExecVersioned(ctx context.Context, q Querier, query string, args ...any) (int64, error) {
+	n, err := q.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, VersionConflict()
+	}
+	return n, nil
+}
+
+// VersionConflict devuelve el error mistake.PreconditionFailed (412) de un conflicto de versión, con
+// ErrVersionConflict como causa
+func VersionConflict() error {
+	return mistake.New(mistake.PreconditionFailed, "el registro fue modificado por otro usuario; vuelva a consultarlo", ErrVersionConflict, "If-Match")
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+func TestVersionSQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         Version
+		typo      string
+		match     string
+		increment string
+	}{
+		{"entero", Version{Kind: VersionInteger, Column: "version"}, "postgres", "version = $3", "version = version + 1"},
+		{"rowversion", Version{Kind: VersionRowversion, Column: "rv"}, "mssql", "CAST(rv AS BIGINT) = @p3", ""},
+		{"xmin", Version{Kind: VersionXmin}, "postgres", "xmin::text::bigint = $3", ""},
+		{"xmin con alias", Version{Kind: VersionXmin, Column: "c.xmin"}, "postgres", "c.xmin::text::bigint = $3", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.v.Validate(tt.typo); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			if got := tt.v.Match(tt.typo, 3); got != tt.match {
+				t.Errorf("Match() = %q; se esperaba %q", got, tt.match)
+			}
+			if got := tt.v.Increment(); got != tt.increment {
+				t.Errorf("Increment() = %q; se esperaba %q", got, tt.increment)
+			}
+		})
+	}
+
+	if err := (Version{Kind: VersionXmin}).Validate("mssql"); err == nil {
+		t.Error("se esperaba error con xmin en SQL Server")
+	}
+	if err := (Version{Kind: VersionInteger, Column: "v; --"}).Validate("postgres"); err == nil {
+		t.Error("se esperaba error con un nombre de columna inválido")
+	}
+}
+
+func TestExecVersioned(t *testing.T) {
+	db, err := newSQLiteCnx(config.DatabaseConfig{Typo: "sqlite", Name: ":memory:"})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE clientes (id INTEGER PRIMARY KEY, nombre TEXT, version INTEGER NOT NULL DEFAULT 1)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+	if _, err := db.Exec(ctx, "INSERT INTO clientes (id, nombre) VALUES (1, 'Ana')"); err != nil {
+		t.Fatalf("Error al insertar: %v", err)
+	}
+
+	v := Version{Kind: VersionInteger, Column: "version"}
+	update := "UPDATE clientes SET nombre = ?, " + v.Increment() + " WHERE id = ? AND " + v.Match("sqlite", 3)
+
+	if _, err := ExecVersioned(ctx, db, update, "Beatriz", 1, 1); err != nil {
+		t.Fatalf("No se esperaba error en la primera actualización, pero obtuvo: %v", err)
+	}
+
+	// La segunda actualización con la versión original pierde la carrera
+	_, err = ExecVersioned(ctx, db, update, "Carla", 1, 1)
+	var mk *mistake.Mistake
+	if !errors.As(err, &mk) || mk.Code() != http.StatusPreconditionFailed {
+		t.Fatalf("ExecVersioned() = %v; se esperaba un error 412", err)
+	}
+
+	var nombre string
+	var version int64
+	if err := db.QueryRow(ctx, "SELECT nombre, "+v.Expr()+" FROM clientes WHERE id = 1").Scan(&nombre, &version); err != nil {
+		t.Fatalf("Error al consultar: %v", err)
+	}
+	if nombre != "Beatriz" || version != 2 {
+		t.Errorf("Se esperaba Beatriz en la versión 2, pero obtuvo %s en la versión %d", nombre, version)
+	}
+}
@@ -2,12 +2,15 @@ package apidocs
 
 import (
 	"embed"
+	"encoding/json"
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/swaggo/swag"
+	"github.com/wfrscltech/vulcano/infra/echo/auth"
 )
 
 const htmlBody = `<!DOCTYPE html>
@@ -50,8 +53,29 @@ const htmlBody = `<!DOCTYPE html>
 //go:embed static/*
 var staticFiles embed.FS
 
+// Option configura la documentación de la API
+type Option func(*options)
+
+type options struct {
+	authz *auth.Registry
+}
+
+// WithAuthorization agrega a cada operación de la especificación los roles, permisos y políticas
+// registrados para su ruta (ver auth.Registry.Require): en la descripción, en la extensión `x-authorization`
+// y como respuesta 403
+func WithAuthorization(r *auth.Registry) Option {
+	return func(o *options) {
+		o.authz = r
+	}
+}
+
 // APIDocsManager Inicializa el endpoint de la documentación de la API
-func APIDocsManager(e *echo.Echo, swinfo *swag.Spec) {
+func APIDocsManager(e *echo.Echo, swinfo *swag.Spec, opts ...Option) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	fsys, err := fs.Sub(staticFiles, "static")
 	if err != nil {
 		slog.Error("Error al cargar recursos estáticos", slog.String("error", err.Error()))
@@ -65,6 +89,74 @@ func APIDocsManager(e *echo.Echo, swinfo *swag.Spec) {
 	})
 
 	e.GET("/doc/spec/swagger.json", func(c echo.Context) error {
-		return c.JSONBlob(http.StatusOK, []byte(swinfo.ReadDoc()))
+		doc := []byte(swinfo.ReadDoc())
+		if o.authz != nil {
+			withAuth, err := documentAuthorization(doc, swinfo.BasePath, o.authz)
+			if err != nil {
+				slog.ErrorContext(c.Request().Context(), "Error al documentar la autorización de la API", slog.String("error", err.Error()))
+			} else {
+				doc = withAuth
+			}
+		}
+		return c.JSONBlob(http.StatusOK, doc)
 	})
 }
+
+// documentAuthorization agrega los requisitos de autorización a las operaciones de la especificación. Las
+// rutas de Echo (`/api/jobs/:id`) se convierten a las de la especificación (`/jobs/{id}`, sin el basePath)
+func documentAuthorization(doc []byte, basePath string, r *auth.Registry) ([]byte, error) {
+	var spec map[string]any
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, err
+	}
+	paths, _ := spec["paths"].(map[string]any)
+
+	r.Each(func(method, path string, reqs []auth.Requirement) {
+		item, _ := paths[specPath(path, basePath)].(map[string]any)
+		op, _ := item[strings.ToLower(method)].(map[string]any)
+		if op == nil {
+			return
+		}
+
+		texts := make([]string, len(reqs))
+		for i, req := range reqs {
+			texts[i] = req.String()
+		}
+		note := "**Autorización:** requiere " + strings.Join(texts, "; ")
+
+		if desc, _ := op["description"].(string); desc != "" {
+			op["description"] = desc + "\n\n" + note
+		} else {
+			op["description"] = note
+		}
+		op["x-authorization"] = reqs
+
+		responses, _ := op["responses"].(map[string]any)
+		if responses == nil {
+			responses = make(map[string]any)
+			op["responses"] = responses
+		}
+		if _, ok := responses["403"]; !ok {
+			responses["403"] = map[string]any{"description": "El usuario no tiene autorización: requiere " + strings.Join(texts, "; ")}
+		}
+	})
+
+	return json.Marshal(spec)
+}
+
+// specPath convierte la ruta de Echo a la de la especificación
+func specPath(path, basePath string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if path == basePath {
+		path = "/"
+	} else if rest, ok := strings.CutPrefix(path, basePath+"/"); ok {
+		path = "/" + rest
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if name, ok := strings.CutPrefix(s, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
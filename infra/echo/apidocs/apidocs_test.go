@@ -0,0 +1,86 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/swaggo/swag"
+	"github.com/wfrscltech/vulcano/infra/echo/auth"
+)
+
+const testDoc = `{
+  "swagger": "2.0",
+  "basePath": "{{.BasePath}}",
+  "paths": {
+    "/reports/{id}": {
+      "get": {"description": "Obtiene un reporte", "responses": {"200": {"description": "OK"}}}
+    },
+    "/health": {
+      "get": {"responses": {"200": {"description": "OK"}}}
+    }
+  }
+}`
+
+// TestWithAuthorization valida que la especificación publique los requisitos de autorización
+func TestWithAuthorization(t *testing.T) {
+	e := echo.New()
+	reg := auth.NewRegistry()
+	reg.Require(auth.Permissions("reports:read"), func(m echo.MiddlewareFunc) *echo.Route {
+		return e.GET("/api/reports/:id", func(c echo.Context) error { return nil }, m)
+	})
+	e.GET("/api/health", func(c echo.Context) error { return nil })
+
+	spec := &swag.Spec{BasePath: "/api", SwaggerTemplate: testDoc, LeftDelim: "{{", RightDelim: "}}"}
+	APIDocsManager(e, spec, WithAuthorization(reg))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc/spec/swagger.json", nil))
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Description   string                     `json:"description"`
+			Authorization []auth.Requirement         `json:"x-authorization"`
+			Responses     map[string]json.RawMessage `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("La especificación no es JSON: %v\n%s", err, rec.Body.String())
+	}
+
+	op := doc.Paths["/reports/{id}"]["get"]
+	if !strings.HasPrefix(op.Description, "Obtiene un reporte") || !strings.Contains(op.Description, "permiso reports:read") {
+		t.Errorf("Descripción = %q", op.Description)
+	}
+	if len(op.Authorization) != 1 || op.Authorization[0].Permissions[0] != "reports:read" {
+		t.Errorf("x-authorization = %+v", op.Authorization)
+	}
+	if _, ok := op.Responses["403"]; !ok {
+		t.Error("Falta la respuesta 403")
+	}
+
+	if health := doc.Paths["/health"]["get"]; health.Description != "" || health.Authorization != nil {
+		t.Errorf("La ruta pública no debería tener requisitos: %+v", health)
+	}
+}
+
+// TestSpecPath valida que el basePath se quite solo como segmento completo de la ruta
+func TestSpecPath(t *testing.T) {
+	tests := []struct {
+		path, basePath, want string
+	}{
+		{"/api/jobs/:id", "/api", "/jobs/{id}"},
+		{"/api", "/api", "/"},
+		{"/apiary", "/api", "/apiary"},
+		{"/api/jobs", "/api/", "/jobs"},
+		{"/jobs/:id/:step", "/", "/jobs/{id}/{step}"},
+	}
+	for _, tt := range tests {
+		if got := specPath(tt.path, tt.basePath); got != tt.want {
+			t.Errorf("specPath(%q, %q): se esperaba %q, obtuvo: %q", tt.path, tt.basePath, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package auth implementa la autenticación con tokens de acceso JWT (`Authorization: Bearer <token>`)
+// firmados con HS256, RS256 o ES256. Las llaves se obtienen de la configuración (ver config.JWTConfig):
+// un secreto compartido, una llave pública PEM o un archivo JWKS local que se vuelve a leer al cambiar.
+//
+//	v, err := auth.NewVerifier(*cfg.JWT)
+//	api := e.Group("/api", auth.JWTMiddleware(v))
+//
+// Los claims del token verificado quedan en el contexto de la petición (ver ClaimsFrom).
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+// HeaderWWWAuthenticate es el encabezado que indica al cliente cómo autenticarse
+const HeaderWWWAuthenticate = echo.HeaderWWWAuthenticate
+
+// descriptions describe los errores en `WWW-Authenticate` (RFC 6750). Sin tildes: el valor del
+// encabezado debe ser ASCII
+var descriptions = map[error]string{
+	ErrTokenMalformed:  "token mal formado",
+	ErrTokenExpired:    "token expirado",
+	ErrTokenIncomplete: "faltan claims obligatorios",
+	ErrTokenNotValid:   "token aun no valido",
+	ErrTokenIssuer:     "emisor no valido",
+	ErrTokenAudience:   "audiencia no valida",
+	ErrTokenSignature:  "firma no valida",
+}
+
+// JWTMiddleware exige un token de acceso válido en el encabezado `Authorization` y deja sus claims en el
+// contexto de la petición. Sin token, o con un token no válido, responde 401 (mistake.Unauthorized) con el
+// encabezado `WWW-Authenticate` que describe el problema
+func JWTMiddleware(v *Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scheme, token, _ := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+			token = strings.TrimSpace(token)
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				c.Response().Header().Set(HeaderWWWAuthenticate, "Bearer")
+				return mistake.New(mistake.Unauthorized, "la petición requiere un token de acceso", errors.New("sin token Bearer"))
+			}
+
+			claims, err := v.Verify(token)
+			if err != nil {
+				c.Response().Header().Set(HeaderWWWAuthenticate,
+					fmt.Sprintf(`Bearer error="invalid_token", error_description="%s"`, descriptions[err]))
+				return mistake.New(mistake.Unauthorized, err.Error(), err)
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(WithClaims(req.Context(), claims)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatalf("Error al firmar el token: %v", err)
+	}
+	return s
+}
+
+func claims(extra jwt.MapClaims) jwt.MapClaims {
+	c := jwt.MapClaims{"sub": "u-1", "iss": "https://sso.example.com", "aud": "erp", "exp": time.Now().Add(time.Hour).Unix()}
+	for k, v := range extra {
+		c[k] = v
+	}
+	return c
+}
+
+// TestVerifier_HS256 valida la firma y los claims registrados
+func TestVerifier_HS256(t *testing.T) {
+	v, err := NewVerifier(config.JWTConfig{
+		Algorithms: []string{config.JWTAlgorithmHS256},
+		Secret:     secret,
+		Issuer:     "https://sso.example.com",
+		Audience:   "erp",
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+
+	c, err := v.Verify(sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{
+		"roles": "admin", "permissions": []string{"reports:read"}, "scope": "jobs:write jobs:read", "tid": "acme",
+	})))
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if c.Subject != "u-1" || c.Audience[0] != "erp" || c.String("tid") != "acme" || c.ExpiresAt.IsZero() {
+		t.Errorf("Claims inesperados: %+v", c)
+	}
+	if strings.Join(c.Roles, ",") != "admin" || strings.Join(c.Permissions, ",") != "reports:read,jobs:write,jobs:read" {
+		t.Errorf("Roles o permisos inesperados: %v %v", c.Roles, c.Permissions)
+	}
+
+	past := time.Now().Add(-time.Hour).Unix()
+	failures := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"Mal formado", "abc.def", ErrTokenMalformed},
+		{"Expirado", sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"exp": past})), ErrTokenExpired},
+		{"Sin exp", sign(t, jwt.SigningMethodHS256, []byte(secret), "", jwt.MapClaims{"iss": "https://sso.example.com", "aud": "erp"}), ErrTokenIncomplete},
+		{"Aún no válido", sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()})), ErrTokenNotValid},
+		{"Otro emisor", sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"iss": "https://evil.com"})), ErrTokenIssuer},
+		{"Otra audiencia", sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"aud": []string{"crm"}})), ErrTokenAudience},
+		{"Otro secreto", sign(t, jwt.SigningMethodHS256, []byte(strings.Repeat("x", 32)), "", claims(nil)), ErrTokenSignature},
+		{"Algoritmo no permitido", sign(t, jwt.SigningMethodHS512, []byte(secret), "", claims(nil)), ErrTokenSignature},
+	}
+	for _, f := range failures {
+		if _, err := v.Verify(f.token); !errors.Is(err, f.err) {
+			t.Errorf("%s: Verify() = %v; se esperaba %v", f.name, err, f.err)
+		}
+	}
+}
+
+// TestVerifier_RS256 valida una llave pública PEM
+func TestVerifier_RS256(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	path := filepath.Join(t.TempDir(), "public.pem")
+	_ = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+
+	v, err := NewVerifier(config.JWTConfig{Algorithms: []string{config.JWTAlgorithmRS256}, PublicKeyFile: path})
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	if _, err := v.Verify(sign(t, jwt.SigningMethodRS256, key, "", claims(nil))); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	// Un token HS256 firmado con la llave pública como secreto no se acepta
+	if _, err := v.Verify(sign(t, jwt.SigningMethodHS256, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), "", claims(nil))); err == nil {
+		t.Error("Se esperaba error con un token HS256")
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys map[string]*ecdsa.PrivateKey, mod time.Time) {
+	t.Helper()
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, k := range keys {
+		set.Keys = append(set.Keys, map[string]string{
+			"kty": "EC", "crv": "P-256", "kid": kid, "alg": "ES256", "use": "sig",
+			"x": base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, 32))),
+			"y": base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, 32))),
+		})
+	}
+	data, _ := json.Marshal(set)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chtimes(path, mod, mod)
+}
+
+// TestVerifier_JWKS valida la elección de la llave por `kid` y la rotación del archivo
+func TestVerifier_JWKS(t *testing.T) {
+	k1, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	k2, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, map[string]*ecdsa.PrivateKey{"k1": k1}, time.Now().Add(-time.Minute))
+
+	v, err := NewVerifier(config.JWTConfig{Algorithms: []string{config.JWTAlgorithmES256}, JWKSFile: path, JWKSRefresh: 3600})
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	if _, err := v.Verify(sign(t, jwt.SigningMethodES256, k1, "k1", claims(nil))); err != nil {
+		t.Errorf("Verify() con k1 = %v", err)
+	}
+
+	// Se rota la llave: un `kid` desconocido vuelve a leer el archivo sin esperar la revisión periódica
+	writeJWKS(t, path, map[string]*ecdsa.PrivateKey{"k1": k1, "k2": k2}, time.Now())
+	v.jwks.mu.Lock()
+	v.jwks.checked = time.Now().Add(-2 * jwksMinReload)
+	v.jwks.mu.Unlock()
+	if _, err := v.Verify(sign(t, jwt.SigningMethodES256, k2, "k2", claims(nil))); err != nil {
+		t.Errorf("Verify() con k2 tras rotar = %v", err)
+	}
+
+	// Sin `kid` la llave es ambigua
+	if _, err := v.Verify(sign(t, jwt.SigningMethodES256, k1, "", claims(nil))); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("Verify() sin kid = %v; se esperaba ErrTokenSignature", err)
+	}
+}
+
+// TestJWTMiddleware valida las respuestas 401 y los claims en el contexto
+func TestJWTMiddleware(t *testing.T) {
+	v, _ := NewVerifier(config.JWTConfig{Algorithms: []string{config.JWTAlgorithmHS256}, Secret: secret})
+	h := JWTMiddleware(v)(func(c echo.Context) error {
+		cl, _ := ClaimsFrom(c.Request().Context())
+		return c.String(http.StatusOK, cl.Subject)
+	})
+
+	call := func(authorization string) (*httptest.ResponseRecorder, int) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		rec := httptest.NewRecorder()
+		err := h(echo.New().NewContext(req, rec))
+
+		var mk *mistake.Mistake
+		if errors.As(err, &mk) {
+			return rec, mk.Code()
+		}
+		return rec, rec.Code
+	}
+
+	if rec, code := call("Bearer " + sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(nil))); code != http.StatusOK || rec.Body.String() != "u-1" {
+		t.Errorf("Con token válido = %d %s", code, rec.Body.String())
+	}
+
+	for _, a := range []string{"", "Basic dXNlcjpwYXNz", "Bearer "} {
+		if rec, code := call(a); code != http.StatusUnauthorized || rec.Header().Get(HeaderWWWAuthenticate) != "Bearer" {
+			t.Errorf("Con %q = %d %q", a, code, rec.Header().Get(HeaderWWWAuthenticate))
+		}
+	}
+
+	expired := sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))
+	rec, code := call("Bearer " + expired)
+	want := `Bearer error="invalid_token", error_description="token expirado"`
+	if code != http.StatusUnauthorized || rec.Header().Get(HeaderWWWAuthenticate) != want {
+		t.Errorf("Con token expirado = %d %q", code, rec.Header().Get(HeaderWWWAuthenticate))
+	}
+}
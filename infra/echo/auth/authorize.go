@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+// HasRole indica si el usuario tiene el rol
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// HasPermission indica si el usuario tiene el permiso
+func (c *Claims) HasPermission(perm string) bool {
+	return slices.Contains(c.Permissions, perm)
+}
+
+// Policy decide si el usuario puede ejecutar la petición. `false` responde 403 (mistake.Forbidden); un
+// error se devuelve tal cual, para que la política pueda responder con su propio mistake
+type Policy func(c echo.Context, claims *Claims) (bool, error)
+
+// Requirement describe lo que exige un middleware de autorización. Se crea con Roles, Permissions o
+// NamedPolicy; Middleware construye el middleware que lo hace cumplir y Registry.Require además lo publica
+// en la documentación de la API
+type Requirement struct {
+	// Basta con uno de estos roles
+	Roles []string `json:"roles,omitempty"`
+	// Se exigen todos estos permisos
+	Permissions []string `json:"permissions,omitempty"`
+	// Nombre de la política
+	Policy string `json:"policy,omitempty"`
+
+	check Policy
+}
+
+// Roles exige que el usuario tenga al menos uno de los roles
+func Roles(roles ...string) Requirement {
+	return Requirement{Roles: roles, check: func(_ echo.Context, claims *Claims) (bool, error) {
+		return slices.ContainsFunc(roles, claims.HasRole), nil
+	}}
+}
+
+// Permissions exige que el usuario tenga todos los permisos
+func Permissions(perms ...string) Requirement {
+	return Requirement{Permissions: perms, check: func(_ echo.Context, claims *Claims) (bool, error) {
+		for _, p := range perms {
+			if !claims.HasPermission(p) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}}
+}
+
+// NamedPolicy autoriza la petición con una política propia, p. ej. que el usuario sea el dueño del
+// registro. El nombre identifica la política en los errores y en la documentación de la API
+func NamedPolicy(name string, p Policy) Requirement {
+	return Requirement{Policy: name, check: p}
+}
+
+// String describe el requisito en texto, p. ej. `rol admin o auditor` o `permisos reports:read y reports:export`
+func (r Requirement) String() string {
+	switch {
+	case len(r.Roles) == 1:
+		return "rol " + r.Roles[0]
+	case len(r.Roles) > 1:
+		return "rol " + strings.Join(r.Roles, " o ")
+	case len(r.Permissions) == 1:
+		return "permiso " + r.Permissions[0]
+	case len(r.Permissions) > 1:
+		return "permisos " + strings.Join(r.Permissions, " y ")
+	}
+	return "política " + r.Policy
+}
+
+// Middleware crea el middleware que hace cumplir el requisito. Se ejecuta después de JWTMiddleware: sin
+// claims en el contexto responde 401 (mistake.Unauthorized); si el usuario no cumple el requisito, 403
+// (mistake.Forbidden)
+func (r Requirement) Middleware() echo.MiddlewareFunc {
+	switch {
+	case r.check != nil:
+		return require(r, r.check)
+	case len(r.Roles) > 0:
+		return Roles(r.Roles...).Middleware()
+	case len(r.Permissions) > 0:
+		return Permissions(r.Permissions...).Middleware()
+	}
+	// Una política declarada sin función no autoriza a nadie
+	return require(r, func(echo.Context, *Claims) (bool, error) {
+		return false, mistake.New(mistake.Internal, "no se pudo verificar la autorización",
+			fmt.Errorf("la política `%s` no tiene función; se debe crear con NamedPolicy", r.Policy))
+	})
+}
+
+// RequireRoles exige que el usuario tenga al menos uno de los roles
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return Roles(roles...).Middleware()
+}
+
+// RequirePermission exige que el usuario tenga todos los permisos
+func RequirePermission(perms ...string) echo.MiddlewareFunc {
+	return Permissions(perms...).Middleware()
+}
+
+// RequirePolicy autoriza la petición con una política propia (ver NamedPolicy)
+func RequirePolicy(name string, p Policy) echo.MiddlewareFunc {
+	return NamedPolicy(name, p).Middleware()
+}
+
+// require crea el middleware de autorización del requisito `r` con la política `p`
+func require(r Requirement, p Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := ClaimsFrom(c.Request().Context())
+			if !ok {
+				return mistake.New(mistake.Unauthorized, "la petición requiere un usuario autenticado",
+					errors.New("sin claims en el contexto; JWTMiddleware debe ejecutarse antes"))
+			}
+
+			allowed, err := p(c, claims)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return mistake.New(mistake.Forbidden, fmt.Sprintf("la operación requiere %s", r),
+					fmt.Errorf("el usuario `%s` no cumple el requisito: %s", claims.Subject, r))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+)
+
+// TestRequire valida las respuestas 401, 403 y 200 de los middlewares de autorización
+func TestRequire(t *testing.T) {
+	user, _ := NewClaims(map[string]any{"sub": "u-1", "roles": []string{"auditor"}, "scope": "reports:read jobs:read"})
+	owner := func(c echo.Context, claims *Claims) (bool, error) {
+		if c.Param("id") == "" {
+			return false, mistake.New(mistake.Invalid, "falta el identificador", nil)
+		}
+		return c.Param("id") == claims.Subject, nil
+	}
+
+	cases := []struct {
+		name   string
+		mw     echo.MiddlewareFunc
+		claims *Claims
+		id     string
+		want   int
+	}{
+		{"Sin claims", RequireRoles("admin"), nil, "", http.StatusUnauthorized},
+		{"Uno de los roles", RequireRoles("admin", "auditor"), user, "", http.StatusOK},
+		{"Sin el rol", RequireRoles("admin"), user, "", http.StatusForbidden},
+		{"Todos los permisos", RequirePermission("reports:read", "jobs:read"), user, "", http.StatusOK},
+		{"Falta un permiso", RequirePermission("reports:read", "reports:export"), user, "", http.StatusForbidden},
+		{"Política cumplida", RequirePolicy("dueño", owner), user, "u-1", http.StatusOK},
+		{"Política no cumplida", RequirePolicy("dueño", owner), user, "u-2", http.StatusForbidden},
+		{"Error de la política", RequirePolicy("dueño", owner), user, "", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.claims != nil {
+				req = req.WithContext(WithClaims(req.Context(), tc.claims))
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+
+			err := tc.mw(func(c echo.Context) error { return c.NoContent(http.StatusOK) })(c)
+			code := rec.Code
+			var mk *mistake.Mistake
+			if errors.As(err, &mk) {
+				code = mk.Code()
+			}
+			if code != tc.want {
+				t.Errorf("Código = %d; se esperaba %d (%v)", code, tc.want, err)
+			}
+		})
+	}
+}
+
+// TestRegistry valida el registro de los requisitos de cada ruta
+func TestRegistry(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		var mk *mistake.Mistake
+		if errors.As(err, &mk) {
+			_ = c.NoContent(mk.Code())
+		}
+	}
+	reg := NewRegistry()
+
+	h := func(c echo.Context) error { return nil }
+	reg.RequireAll(Roles("admin"), func(m echo.MiddlewareFunc) []*echo.Route {
+		api := e.Group("/api", m)
+		return append([]*echo.Route{
+			reg.Require(Permissions("reports:read"), func(m echo.MiddlewareFunc) *echo.Route {
+				return api.GET("/reports/:id", h, m)
+			}),
+			api.POST("/reports", h),
+		}, api.Match([]string{http.MethodPut, http.MethodPatch}, "/reports/:id", h)...)
+	})
+	e.GET("/public", h)
+
+	reqs, ok := reg.Lookup(http.MethodGet, "/api/reports/:id")
+	if !ok || len(reqs) != 2 || reqs[0].String() != "rol admin" || reqs[1].String() != "permiso reports:read" {
+		t.Errorf("Lookup(GET /api/reports/:id) = %v, %v", reqs, ok)
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch} {
+		path := "/api/reports/:id"
+		if method == http.MethodPost {
+			path = "/api/reports"
+		}
+		if reqs, ok := reg.Lookup(method, path); !ok || len(reqs) != 1 {
+			t.Errorf("Lookup(%s %s) = %v, %v", method, path, reqs, ok)
+		}
+	}
+	if reqs, ok := reg.Lookup(http.MethodGet, "/public"); ok {
+		t.Errorf("Lookup(GET /public) = %v; no se esperaban requisitos", reqs)
+	}
+
+	// El middleware registrado hace cumplir el requisito documentado
+	user, _ := NewClaims(map[string]any{"sub": "u-1", "roles": []string{"admin"}})
+	for _, tc := range []struct {
+		claims *Claims
+		want   int
+	}{{nil, http.StatusUnauthorized}, {user, http.StatusForbidden}} {
+		req := httptest.NewRequest(http.MethodGet, "/api/reports/1", nil)
+		if tc.claims != nil {
+			req = req.WithContext(WithClaims(req.Context(), tc.claims))
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("Código = %d; se esperaba %d", rec.Code, tc.want)
+		}
+	}
+}
+
+// TestRequirementMiddleware valida el middleware de un requisito creado sin constructor
+func TestRequirementMiddleware(t *testing.T) {
+	user, _ := NewClaims(map[string]any{"sub": "u-1", "roles": []string{"admin"}})
+	for _, tc := range []struct {
+		req  Requirement
+		want int
+	}{
+		{Requirement{Roles: []string{"admin"}}, http.StatusOK},
+		{Requirement{Permissions: []string{"reports:read"}}, http.StatusForbidden},
+		{Requirement{Policy: "sin función"}, http.StatusInternalServerError},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(WithClaims(context.Background(), user))
+		rec := httptest.NewRecorder()
+		err := tc.req.Middleware()(func(c echo.Context) error { return c.NoContent(http.StatusOK) })(echo.New().NewContext(req, rec))
+		code := rec.Code
+		var mk *mistake.Mistake
+		if errors.As(err, &mk) {
+			code = mk.Code()
+		}
+		if code != tc.want {
+			t.Errorf("%s: código = %d; se esperaba %d (%v)", tc.req, code, tc.want, err)
+		}
+	}
+}
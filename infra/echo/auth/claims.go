@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims son los claims de un token verificado
+type Claims struct {
+	// Sujeto del token (`sub`), normalmente el identificador del usuario
+	Subject string
+	// Emisor (`iss`)
+	Issuer string
+	// Audiencias (`aud`)
+	Audience []string
+	// Vencimiento (`exp`)
+	ExpiresAt time.Time
+	// Emisión (`iat`); cero si el token no lo indica
+	IssuedAt time.Time
+	// Identificador del token (`jti`)
+	ID string
+	// Roles del claim `roles` (lista o texto)
+	Roles []string
+	// Permisos del claim `permissions` (lista o texto) y del claim `scope` (separados por espacios)
+	Permissions []string
+
+	raw map[string]any
+}
+
+// Get devuelve un claim cualquiera del token, tal como se decodificó del JSON
+func (c *Claims) Get(name string) (any, bool) {
+	v, ok := c.raw[name]
+	return v, ok
+}
+
+// String devuelve un claim de texto; vacío si no existe o no es un texto
+func (c *Claims) String(name string) string {
+	s, _ := c.raw[name].(string)
+	return s
+}
+
+// NewClaims construye los claims a partir del contenido JSON decodificado de un token. JWTMiddleware los
+// construye tras verificar el token; también sirve para preparar el contexto en las pruebas de los handlers
+func NewClaims(m map[string]any) (*Claims, error) {
+	var d struct {
+		jwt.RegisteredClaims
+		Roles       jwt.ClaimStrings `json:"roles"`
+		Permissions jwt.ClaimStrings `json:"permissions"`
+		Scope       string           `json:"scope"`
+	}
+
+	// Se reutiliza la decodificación de jwt para `aud` (texto o lista) y las fechas numéricas
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+
+	c := &Claims{
+		Subject:     d.Subject,
+		Issuer:      d.Issuer,
+		Audience:    d.Audience,
+		ID:          d.ID,
+		Roles:       d.Roles,
+		Permissions: append(d.Permissions, strings.Fields(d.Scope)...),
+		raw:         m,
+	}
+	if d.ExpiresAt != nil {
+		c.ExpiresAt = d.ExpiresAt.Time
+	}
+	if d.IssuedAt != nil {
+		c.IssuedAt = d.IssuedAt.Time
+	}
+	return c, nil
+}
+
+type claimsKey struct{}
+
+// WithClaims devuelve un contexto con los claims del usuario autenticado
+func WithClaims(ctx context.Context, c *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, c)
+}
+
+// ClaimsFrom devuelve los claims que JWTMiddleware dejó en el contexto de la petición
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(*Claims)
+	return c, ok && c != nil
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwksMinReload limita las relecturas del archivo cuando llega un `kid` desconocido
+const jwksMinReload = time.Second
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// jwks mantiene las llaves de un archivo JWKS local. El archivo se vuelve a leer cuando cambia su fecha de
+// modificación, revisándola cada `refresh` o antes si llega un `kid` desconocido (una llave recién rotada)
+type jwks struct {
+	path    string
+	refresh time.Duration
+
+	mu      sync.Mutex
+	keys    []jwkKey
+	modTime time.Time
+	checked time.Time
+}
+
+func newJWKS(path string, refresh time.Duration) *jwks {
+	return &jwks{path: path, refresh: refresh}
+}
+
+// get devuelve la llave del `kid` para el algoritmo. Sin `kid`, solo si hay una única llave que sirva
+func (j *jwks) get(kid, alg string) (crypto.PublicKey, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	k, ok := j.find(kid, alg)
+	since := time.Since(j.checked)
+	if since >= j.refresh || (!ok && since >= jwksMinReload) {
+		if err := j.reload(); err != nil {
+			slog.Warn("No se pudo actualizar el archivo JWKS; se mantienen las llaves anteriores",
+				slog.String("path", j.path), slog.String("error", err.Error()))
+		}
+		k, ok = j.find(kid, alg)
+	}
+	return k, ok
+}
+
+func (j *jwks) find(kid, alg string) (crypto.PublicKey, bool) {
+	var found []crypto.PublicKey
+	for _, k := range j.keys {
+		if (kid == "" || k.kid == kid) && (k.alg == "" || k.alg == alg) && matches(k.key, alg) {
+			found = append(found, k.key)
+		}
+	}
+	if len(found) != 1 {
+		return nil, false
+	}
+	return found[0], true
+}
+
+// load lee el archivo por primera vez; a diferencia de reload, un archivo sin llaves es un error
+func (j *jwks) load() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.reload(); err != nil {
+		return err
+	}
+	if len(j.keys) == 0 {
+		return fmt.Errorf("auth: el archivo JWKS `%s` no tiene llaves RSA o EC P-256 de firma", j.path)
+	}
+	return nil
+}
+
+func (j *jwks) reload() error {
+	j.checked = time.Now()
+
+	info, err := os.Stat(j.path)
+	if err != nil {
+		return fmt.Errorf("auth: no se pudo leer el archivo JWKS: %w", err)
+	}
+	if info.ModTime().Equal(j.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		return fmt.Errorf("auth: no se pudo leer el archivo JWKS: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("auth: el archivo JWKS `%s` no es válido: %w", j.path, err)
+	}
+
+	keys := make([]jwkKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return fmt.Errorf("auth: la llave `%s` del archivo JWKS no es válida: %w", k.Kid, err)
+		}
+		if pub != nil {
+			keys = append(keys, jwkKey{kid: k.Kid, alg: k.Alg, key: pub})
+		}
+	}
+
+	j.keys, j.modTime = keys, info.ModTime()
+	return nil
+}
+
+// publicKey decodifica la llave; devuelve nil para los tipos no soportados
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponente RSA no válido")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if x.BitLen() > 256 || y.BitLen() > 256 {
+			return nil, errors.New("coordenadas P-256 no válidas")
+		}
+		// ecdh valida que el punto pertenezca a la curva
+		point := append([]byte{4}, append(x.FillBytes(make([]byte, 32)), y.FillBytes(make([]byte, 32))...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, errors.New("el punto no pertenece a la curva P-256")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("falta un parámetro de la llave")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
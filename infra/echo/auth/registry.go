@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Registry guarda los requisitos de autorización de cada ruta, para publicarlos en la documentación de la
+// API (ver apidocs.WithAuthorization)
+type Registry struct {
+	mu     sync.RWMutex
+	routes map[routeKey][]Requirement
+}
+
+// NewRegistry crea un registro de requisitos vacío
+func NewRegistry() *Registry {
+	return &Registry{routes: make(map[routeKey][]Requirement)}
+}
+
+// Require registra con `add` una ruta que exige `req` y publica el requisito en la documentación. `add`
+// recibe el middleware creado con req.Middleware(), de modo que lo documentado y lo que se exige son el
+// mismo valor:
+//
+//	authz := auth.NewRegistry()
+//	authz.Require(auth.Permissions("reports:read"), func(m echo.MiddlewareFunc) *echo.Route {
+//		return api.GET("/reports", listReports, m)
+//	})
+//	apidocs.APIDocsManager(e, docs.SwaggerInfo, apidocs.WithAuthorization(authz))
+func (r *Registry) Require(req Requirement, add func(m echo.MiddlewareFunc) *echo.Route) *echo.Route {
+	route := add(req.Middleware())
+	r.record(req, route)
+	return route
+}
+
+// RequireAll es como Require para varias rutas: las de Any o Match, o todas las de un grupo
+//
+//	authz.RequireAll(auth.Roles("admin"), func(m echo.MiddlewareFunc) []*echo.Route {
+//		admin := api.Group("/admin", m)
+//		return []*echo.Route{admin.GET("/users", listUsers), admin.DELETE("/users/:id", deleteUser)}
+//	})
+func (r *Registry) RequireAll(req Requirement, add func(m echo.MiddlewareFunc) []*echo.Route) []*echo.Route {
+	routes := add(req.Middleware())
+	r.record(req, routes...)
+	return routes
+}
+
+// record agrega el requisito a las rutas. Se antepone a los ya registrados porque un Require anidado (la
+// ruta dentro del grupo) se registra antes y se ejecuta después
+func (r *Registry) record(req Requirement, routes ...*echo.Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, route := range routes {
+		key := routeKey{route.Method, route.Path}
+		r.routes[key] = append([]Requirement{req}, r.routes[key]...)
+	}
+}
+
+// Lookup devuelve los requisitos de la ruta, con el método y la ruta tal como se registraron en Echo
+// (p. ej. `GET` y `/api/jobs/:id`). Si hay varios, se exigen todos
+func (r *Registry) Lookup(method, path string) ([]Requirement, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reqs, ok := r.routes[routeKey{method, path}]
+	return reqs, ok
+}
+
+// Each recorre las rutas con requisitos
+func (r *Registry) Each(f func(method, path string, reqs []Requirement)) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for k, reqs := range r.routes {
+		f(k.method, k.path, reqs)
+	}
+}
+
+type routeKey struct {
+	method string
+	path   string
+}
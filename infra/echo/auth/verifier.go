@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/fn"
+)
+
+// Revisión por defecto del archivo JWKS
+const DefaultJWKSRefresh = 5 * time.Minute
+
+// Errores de verificación, que JWTMiddleware informa en `WWW-Authenticate`
+var (
+	ErrTokenMalformed  = errors.New("el token de acceso está mal formado")
+	ErrTokenExpired    = errors.New("el token de acceso expiró")
+	ErrTokenIncomplete = errors.New("al token de acceso le falta un claim obligatorio (`exp`, `iss` o `aud`)")
+	ErrTokenNotValid   = errors.New("el token de acceso todavía no es válido")
+	ErrTokenIssuer     = errors.New("el emisor del token de acceso no es válido")
+	ErrTokenAudience   = errors.New("la audiencia del token de acceso no es válida")
+	ErrTokenSignature  = errors.New("la firma del token de acceso no es válida")
+)
+
+// Verifier verifica la firma y los claims registrados de los tokens de acceso
+type Verifier struct {
+	parser    *jwt.Parser
+	secret    []byte
+	publicKey crypto.PublicKey
+	jwks      *jwks
+}
+
+// NewVerifier crea el verificador de la configuración (ver config.JWTConfig, validada al cargarla). Lee la
+// llave pública y el archivo JWKS, si se indican
+func NewVerifier(cfg config.JWTConfig) (*Verifier, error) {
+	if err := cfg.IsValid(); err != nil {
+		return nil, err
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Duration(cfg.Leeway) * time.Second),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v := &Verifier{parser: jwt.NewParser(opts...)}
+	if cfg.Secret != "" {
+		v.secret = []byte(cfg.Secret)
+	}
+
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: no se pudo leer la llave pública: %w", err)
+		}
+		if v.publicKey, err = parsePublicKey(data); err != nil {
+			return nil, fmt.Errorf("auth: la llave pública `%s` no es válida: %w", cfg.PublicKeyFile, err)
+		}
+	}
+
+	if cfg.JWKSFile != "" {
+		refresh := time.Duration(cfg.JWKSRefresh) * time.Second
+		if refresh == 0 {
+			refresh = DefaultJWKSRefresh
+		}
+		v.jwks = newJWKS(cfg.JWKSFile, refresh)
+		if err := v.jwks.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// Verify verifica el token y devuelve sus claims. Los errores son los Err* de este paquete
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	if fn.ValidateRegexp("JWT", token, "token mal formado") != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	var m jwt.MapClaims
+	_, err := v.parser.ParseWithClaims(token, &m, v.key)
+	switch {
+	case err == nil:
+		c, err := NewClaims(m)
+		if err != nil {
+			return nil, ErrTokenMalformed
+		}
+		return c, nil
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return nil, ErrTokenIncomplete
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return nil, ErrTokenNotValid
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return nil, ErrTokenIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return nil, ErrTokenAudience
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return nil, ErrTokenMalformed
+	default:
+		return nil, ErrTokenSignature
+	}
+}
+
+// key elige la llave según el algoritmo y el `kid` del token
+func (v *Verifier) key(t *jwt.Token) (any, error) {
+	alg := t.Method.Alg()
+	if alg == config.JWTAlgorithmHS256 {
+		if v.secret == nil {
+			return nil, errors.New("no hay secreto para HS256")
+		}
+		return v.secret, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	if v.jwks != nil {
+		if k, ok := v.jwks.get(kid, alg); ok {
+			return k, nil
+		}
+	}
+	if v.publicKey != nil && matches(v.publicKey, alg) {
+		return v.publicKey, nil
+	}
+	return nil, fmt.Errorf("no hay una llave %s para el kid `%s`", alg, kid)
+}
+
+// matches indica si la llave sirve para el algoritmo
+func matches(k crypto.PublicKey, alg string) bool {
+	switch k := k.(type) {
+	case *rsa.PublicKey:
+		return alg == config.JWTAlgorithmRS256
+	case *ecdsa.PublicKey:
+		return alg == config.JWTAlgorithmES256 && k.Curve == elliptic.P256()
+	}
+	return false
+}
+
+// parsePublicKey lee una llave pública PEM (PKIX, PKCS#1 o un certificado)
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no contiene un bloque PEM")
+	}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
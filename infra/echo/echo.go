@@ -1,23 +1,46 @@
 package echo
 
 import (
-	"context"
 	"log/slog"
-	"net/http"
 	"os"
 
 	"github.com/labstack/echo/v4"
 	echom "github.com/labstack/echo/v4/middleware"
+	"github.com/wfrscltech/vulcano/config"
 	"github.com/wfrscltech/vulcano/infra/echo/middleware"
 )
 
+// Option configura la instancia de Echo
+type Option func(*options)
+
+type options struct {
+	cors config.CORSConfig
+}
+
+// WithCORS aplica la política CORS indicada (ver config.ServerConfig.CORS). Sin esta opción se permite
+// cualquier origen, sin credenciales, con los métodos y encabezados expuestos por defecto de
+// middleware.CORSMiddleware
+func WithCORS(cfg *config.CORSConfig) Option {
+	return func(o *options) {
+		if cfg != nil {
+			o.cors = *cfg
+		}
+	}
+}
+
 // NewEchoInstance Crea e inicializa una nueva instancia de Echo
-func NewEchoInstance(logger *slog.Logger, version, buildTime, commitHash string) *echo.Echo {
+func NewEchoInstance(logger *slog.Logger, version, buildTime, commitHash string, opts ...Option) *echo.Echo {
+	o := options{cors: config.CORSConfig{AllowOrigins: []string{"*"}}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	e := echo.New()
 	e.HideBanner = true
 	e.HidePort = true
 	e.Logger.SetOutput(os.Stdout)
 
+	e.Use(middleware.RequestIDMiddleware)
 	e.Use(echom.RequestLoggerWithConfig(echom.RequestLoggerConfig{
 		LogStatus:   true,
 		LogURI:      true,
@@ -25,12 +48,12 @@ func NewEchoInstance(logger *slog.Logger, version, buildTime, commitHash string)
 		HandleError: true, // forwards error to the global error handler, so it can decide appropriate status code
 		LogValuesFunc: func(c echo.Context, v echom.RequestLoggerValues) error {
 			if v.Error == nil {
-				logger.LogAttrs(context.Background(), slog.LevelInfo, "REQUEST",
+				logger.LogAttrs(c.Request().Context(), slog.LevelInfo, "REQUEST",
 					slog.String("uri", v.URI),
 					slog.Int("status", v.Status),
 				)
 			} else {
-				logger.LogAttrs(context.Background(), slog.LevelError, "REQUEST_ERROR",
+				logger.LogAttrs(c.Request().Context(), slog.LevelError, "REQUEST_ERROR",
 					slog.String("uri", v.URI),
 					slog.Int("status", v.Status),
 					slog.String("err", v.Error.Error()),
@@ -40,11 +63,7 @@ func NewEchoInstance(logger *slog.Logger, version, buildTime, commitHash string)
 		},
 	}))
 	e.Use(middleware.ProblemMiddleware)
-	e.Use(echom.CORSWithConfig(echom.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions, http.MethodPut},
-		AllowHeaders: []string{"*"},
-	}))
+	e.Use(middleware.CORSMiddleware(o.cors))
 
 	slog.SetDefault(logger)
 
@@ -0,0 +1,94 @@
+// Package etag expone la versión de los registros (ver database.Version) como encabezado `ETag` y exige
+// `If-Match` en las modificaciones, para el control de concurrencia optimista: si el registro cambió
+// desde que el cliente lo leyó, la actualización se rechaza con 412 en lugar de sobrescribir los cambios.
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Encabezados HTTP del control de concurrencia
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// Format devuelve el ETag fuerte de una versión
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 36) + `"`
+}
+
+// Parse devuelve la versión de un ETag generado por Format
+func Parse(tag string) (int64, error) {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "W/") {
+		return 0, errors.New("el ETag débil no admite comparación fuerte")
+	}
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, errors.New("el ETag debe estar entre comillas")
+	}
+	return strconv.ParseInt(tag[1:len(tag)-1], 36, 64)
+}
+
+// Set agrega el encabezado `ETag` con la versión del registro a la respuesta
+func Set(c echo.Context, version int64) {
+	c.Response().Header().Set(HeaderETag, Format(version))
+}
+
+// IfMatch devuelve la versión indicada en el encabezado `If-Match`. Si falta devuelve un error
+// mistake.PreconditionRequired (428); si no corresponde a un ETag de Format, un mistake.PreconditionFailed
+// (412), porque no puede coincidir con ninguna versión
+func IfMatch(c echo.Context) (int64, error) {
+	v := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	if v == "" {
+		return 0, required()
+	}
+	if v == "*" || strings.Contains(v, ",") {
+		return 0, mistake.New(mistake.Invalid, "el encabezado `If-Match` debe indicar un único ETag del registro", errors.New("If-Match sin ETag único"), HeaderIfMatch)
+	}
+
+	version, err := Parse(v)
+	if err != nil {
+		return 0, mistake.New(mistake.PreconditionFailed, "el encabezado `If-Match` no corresponde a una versión del registro", err, HeaderIfMatch)
+	}
+	return version, nil
+}
+
+// Check compara la versión actual del registro con la del encabezado `If-Match` y devuelve
+// database.VersionConflict si no coinciden. Sirve para validar antes de modificar cuando la actualización
+// no puede condicionarse por la versión (ver database.ExecVersioned)
+func Check(c echo.Context, current int64) error {
+	version, err := IfMatch(c)
+	if err != nil {
+		return err
+	}
+	if version != current {
+		return database.VersionConflict()
+	}
+	return nil
+}
+
+// Require exige el encabezado `If-Match` en las peticiones PUT, PATCH y DELETE, que se rechazan con 428
+// si no lo indican
+func Require(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		switch c.Request().Method {
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			if strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch)) == "" {
+				return required()
+			}
+		}
+		return next(c)
+	}
+}
+
+func required() error {
+	return mistake.New(mistake.PreconditionRequired, "se requiere el encabezado `If-Match` con el ETag del registro", errors.New("falta If-Match"), HeaderIfMatch)
+}
@@ -0,0 +1,77 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/infra/echo/middleware"
+)
+
+// version simula la versión actual del registro editado
+const version int64 = 1234
+
+func serve(method, ifMatch string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(middleware.ProblemMiddleware)
+	h := func(c echo.Context) error {
+		if c.Request().Method != http.MethodGet {
+			if err := Check(c, version); err != nil {
+				return err
+			}
+		}
+		Set(c, version)
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/", h, Require)
+	e.PUT("/", h, Require)
+
+	req := httptest.NewRequest(method, "/", nil)
+	if ifMatch != "" {
+		req.Header.Set(HeaderIfMatch, ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestParse(t *testing.T) {
+	for _, v := range []int64{0, 1, version, 1 << 62} {
+		got, err := Parse(Format(v))
+		if err != nil || got != v {
+			t.Errorf("Parse(Format(%d)) = %d, %v", v, got, err)
+		}
+	}
+	for _, tag := range []string{"", `W/"ya"`, "ya", `"no-base36!"`} {
+		if _, err := Parse(tag); err == nil {
+			t.Errorf("Parse(%q): se esperaba error", tag)
+		}
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	rec := serve(http.MethodGet, "")
+	if rec.Code != http.StatusOK || rec.Header().Get(HeaderETag) != Format(version) {
+		t.Fatalf("GET: se esperaba el ETag %s, obtuvo: %d %q", Format(version), rec.Code, rec.Header().Get(HeaderETag))
+	}
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		code    int
+	}{
+		{"misma versión", Format(version), http.StatusOK},
+		{"sin If-Match", "", http.StatusPreconditionRequired},
+		{"versión anterior", Format(version - 1), http.StatusPreconditionFailed},
+		{"ETag débil", "W/" + Format(version), http.StatusPreconditionFailed},
+		{"comodín", "*", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(http.MethodPut, tt.ifMatch); rec.Code != tt.code {
+				t.Errorf("PUT: se esperaba %d, obtuvo: %d %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
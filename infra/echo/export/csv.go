@@ -0,0 +1,46 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// csvWriter escribe los registros como CSV con una fila de encabezados
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w *bufio.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) begin(columns []database.ColumnType) error {
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+
+	c.record = make([]string, len(columns))
+	return c.w.Write(header)
+}
+
+func (c *csvWriter) row(values []any) error {
+	for i, v := range values {
+		c.record[i] = text(v)
+	}
+
+	// csv.Writer escribe sobre el buffer de la respuesta, que se vacía periódicamente desde stream
+	if err := c.w.Write(c.record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,237 @@
+// Package export escribe conjuntos de registros arbitrarios directamente en la respuesta HTTP como JSON,
+// NDJSON, CSV o XLSX, fila por fila y sin acumular el resultado en memoria.
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Format identifica el formato de salida
+type Format string
+
+const (
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	CSV    Format = "csv"
+	XLSX   Format = "xlsx"
+)
+
+// Tipos MIME de cada formato, usados para la negociación por el header Accept
+const (
+	MIMEJSON   = "application/json"
+	MIMENDJSON = "application/x-ndjson"
+	MIMECSV    = "text/csv"
+	MIMEXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var mimeTypes = map[Format]string{
+	JSON:   MIMEJSON,
+	NDJSON: MIMENDJSON,
+	CSV:    MIMECSV,
+	XLSX:   MIMEXLSX,
+}
+
+// Cantidad de filas entre cada vaciado del buffer hacia el cliente
+const flushEvery = 500
+
+// Option configura la escritura de la respuesta
+type Option func(*options)
+
+type options struct {
+	param    string
+	filename string
+	sheet    string
+	formats  []Format
+}
+
+// WithFormatParam cambia el nombre del parámetro de consulta que elige el formato (por defecto `format`)
+func WithFormatParam(name string) Option {
+	return func(o *options) {
+		o.param = name
+	}
+}
+
+// WithFilename indica el nombre base del archivo descargado; se agrega la extensión del formato y se envía
+// como adjunto en el header Content-Disposition
+func WithFilename(name string) Option {
+	return func(o *options) {
+		o.filename = name
+	}
+}
+
+// WithSheetName cambia el nombre de la hoja en las respuestas XLSX (por defecto `Datos`)
+func WithSheetName(name string) Option {
+	return func(o *options) {
+		o.sheet = name
+	}
+}
+
+// WithFormats restringe los formatos aceptados por el endpoint
+func WithFormats(formats ...Format) Option {
+	return func(o *options) {
+		o.formats = formats
+	}
+}
+
+// rowWriter escribe un conjunto de registros en un formato concreto
+type rowWriter interface {
+	begin(columns []database.ColumnType) error
+	row(values []any) error
+	end() error
+}
+
+// Write escribe `rows` en la respuesta con el formato elegido por el parámetro `format` o, en su
+// ausencia, por el header Accept (JSON por defecto). Los registros siempre se cierran.
+//
+// Los errores de negociación y de lectura de columnas se devuelven para que ProblemMiddleware los
+// formatee; una vez iniciada la respuesta ya no es posible cambiar el código HTTP, por lo que los errores
+// posteriores se registran en el log y la respuesta queda truncada
+func Write(c echo.Context, rows database.Rows, opts ...Option) error {
+	defer rows.Close()
+
+	o := options{param: "format", sheet: "Datos", formats: []Format{JSON, NDJSON, CSV, XLSX}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	format, err := Negotiate(c, o.param, o.formats...)
+	if err != nil {
+		return err
+	}
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType(format))
+	if o.filename != "" {
+		res.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{
+			"filename": o.filename + "." + string(format),
+		}))
+	}
+	res.WriteHeader(http.StatusOK)
+
+	buf := bufio.NewWriterSize(res, 32*1024)
+	w := newRowWriter(format, buf, o)
+
+	err = stream(rows, columns, w, func() error {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error al transmitir registros",
+			slog.String("format", string(format)),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return nil
+}
+
+// stream recorre los registros y los entrega al escritor, vaciando el buffer periódicamente
+func stream(rows database.Rows, columns []database.ColumnType, w rowWriter, flush func() error) error {
+	if err := w.begin(columns); err != nil {
+		return err
+	}
+
+	scan := database.ScanValues(len(columns))
+	n := 0
+	for values, err := range database.All(rows, scan) {
+		if err != nil {
+			return err
+		}
+		for i := range values {
+			values[i] = normalize(columns[i], values[i])
+		}
+		if err := w.row(values); err != nil {
+			return err
+		}
+
+		n++
+		if n%flushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.end()
+}
+
+// Negotiate elige el formato de salida a partir del parámetro de consulta `param` o del header Accept.
+// Devuelve un error mistake.Invalid si el parámetro indica un formato no soportado
+func Negotiate(c echo.Context, param string, allowed ...Format) (Format, error) {
+	if len(allowed) == 0 {
+		allowed = []Format{JSON, NDJSON, CSV, XLSX}
+	}
+
+	if v := strings.ToLower(strings.TrimSpace(c.QueryParam(param))); v != "" {
+		for _, f := range allowed {
+			if string(f) == v {
+				return f, nil
+			}
+		}
+		return "", mistake.New(
+			mistake.Invalid,
+			fmt.Sprintf("el formato `%s` no es válido. Las opciones válidas son: %q", v, allowed),
+			errors.New("formato de exportación no soportado"),
+			param,
+		)
+	}
+
+	for _, part := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		for _, f := range allowed {
+			if mimeTypes[f] == mt {
+				return f, nil
+			}
+		}
+	}
+
+	return allowed[0], nil
+}
+
+func contentType(f Format) string {
+	switch f {
+	case CSV:
+		return MIMECSV + "; charset=utf-8"
+	case JSON:
+		return echo.MIMEApplicationJSON
+	default:
+		return mimeTypes[f]
+	}
+}
+
+func newRowWriter(f Format, w *bufio.Writer, o options) rowWriter {
+	switch f {
+	case NDJSON:
+		return &jsonWriter{w: w, lines: true}
+	case CSV:
+		return newCSVWriter(w)
+	case XLSX:
+		return newXLSXWriter(w, o.sheet)
+	default:
+		return &jsonWriter{w: w}
+	}
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/databasetest"
+)
+
+// reportRows prepara un conjunto de registros de prueba con tipos variados
+func reportRows(t *testing.T) database.Rows {
+	t.Helper()
+
+	db := databasetest.New(t)
+	db.ExpectQuery("SELECT * FROM ventas").WillReturnRows(
+		databasetest.NewRows("id", "cliente", "total", "fecha", "activo").
+			WithTypes("INT", "NVARCHAR", "DECIMAL", "DATE", "BIT").
+			AddRow(int64(1), "Ana, S.A.", []byte("10.50"), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), true).
+			AddRow(int64(2), "Luis", []byte("7"), time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), nil),
+	)
+
+	rows, err := db.Query(context.Background(), "SELECT * FROM ventas")
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	return rows
+}
+
+func request(target, accept string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+// TestWrite_Formats valida la salida de cada formato de texto
+func TestWrite_Formats(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "JSON por defecto",
+			target:      "/ventas",
+			contentType: MIMEJSON,
+			body:        `[{"id":1,"cliente":"Ana, S.A.","total":10.50,"fecha":"2025-03-01","activo":true},{"id":2,"cliente":"Luis","total":7,"fecha":"2025-03-02","activo":null}]`,
+		},
+		{
+			name:        "NDJSON por header Accept",
+			target:      "/ventas",
+			accept:      "text/html, application/x-ndjson;q=0.9",
+			contentType: MIMENDJSON,
+			body:        "{\"id\":1,\"cliente\":\"Ana, S.A.\",\"total\":10.50,\"fecha\":\"2025-03-01\",\"activo\":true}\n{\"id\":2,\"cliente\":\"Luis\",\"total\":7,\"fecha\":\"2025-03-02\",\"activo\":null}\n",
+		},
+		{
+			name:        "CSV por parámetro",
+			target:      "/ventas?format=csv",
+			accept:      MIMEJSON,
+			contentType: "text/csv; charset=utf-8",
+			body:        "id,cliente,total,fecha,activo\n1,\"Ana, S.A.\",10.50,2025-03-01,true\n2,Luis,7,2025-03-02,\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := request(tt.target, tt.accept)
+			if err := Write(c, reportRows(t)); err != nil {
+				t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); got != tt.contentType {
+				t.Errorf("Se esperaba Content-Type %q, obtuvo: %q", tt.contentType, got)
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("Cuerpo inesperado:\n%s\nse esperaba:\n%s", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+// TestWrite_XLSX valida que el libro generado sea un zip con la hoja y sus celdas
+func TestWrite_XLSX(t *testing.T) {
+	c, rec := request("/ventas?format=xlsx", "")
+	if err := Write(c, reportRows(t), WithFilename("ventas")); err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+
+	if got := rec.Header().Get(echo.HeaderContentDisposition); got != `attachment; filename=ventas.xlsx` {
+		t.Errorf("Content-Disposition inesperado: %q", got)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatalf("La respuesta no es un zip válido: %v", err)
+	}
+
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			b, _ := io.ReadAll(r)
+			sheet = string(b)
+		}
+	}
+
+	for _, want := range []string{
+		`<c r="B1" t="inlineStr" s="2"><is><t xml:space="preserve">cliente</t></is></c>`,
+		`<c r="A2"><v>1</v></c>`,
+		`<t xml:space="preserve">Ana, S.A.</t>`,
+		`<c r="C2"><v>10.50</v></c>`,
+		`<c r="E2" t="b"><v>1</v></c>`,
+		`<c r="D2" s="3"><v>45717</v></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Se esperaba que la hoja contenga %q:\n%s", want, sheet)
+		}
+	}
+}
+
+// TestNegotiate_InvalidFormat valida el error de formato no soportado
+func TestNegotiate_InvalidFormat(t *testing.T) {
+	c, _ := request("/ventas?format=pdf", "")
+	_, err := Negotiate(c, "format", JSON, CSV)
+
+	mk, ok := err.(*mistake.Mistake)
+	if !ok || mk.Code() != http.StatusBadRequest {
+		t.Fatalf("Se esperaba un mistake.Invalid, obtuvo: %v", err)
+	}
+}
+
+// TestNormalize valida la conversión de fechas entregadas como texto y de flotantes no representables
+func TestNormalize(t *testing.T) {
+	ts := time.Date(2025, 3, 1, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dbType   string
+		value    any
+		expected any
+	}{
+		{"DATE como time.Time", "DATE", ts, date(ts)},
+		{"DATE como texto", "DATE", "2025-03-01", date(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))},
+		{"DATETIME como texto", "DATETIME", "2025-03-01 14:30:00", ts},
+		{"TIMESTAMP como bytes", "TIMESTAMP", []byte("2025-03-01T14:30:00Z"), ts},
+		{"Texto que no es fecha", "DATETIME", "pendiente", "pendiente"},
+		{"NaN", "FLOAT", math.NaN(), nil},
+		{"Infinito", "REAL", float32(math.Inf(-1)), nil},
+		{"Flotante", "FLOAT", 1.5, 1.5},
+		{"JSONB como objeto", "JSONB", map[string]any{"a": 1.0}, json.RawMessage(`{"a":1}`)},
+		{"JSONB como arreglo", "JSONB", []any{"x", 2.0}, json.RawMessage(`["x",2]`)},
+		{"Arreglo de texto", "_TEXT", []string{"x", "y"}, json.RawMessage(`["x","y"]`)},
+		{"Arreglo de enteros", "_INT8", []int64{1, 2}, json.RawMessage(`[1,2]`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalize(database.ColumnType{Name: "c", DatabaseType: tt.dbType}, tt.value)
+			if g, ok := got.(json.RawMessage); ok {
+				if e, ok := tt.expected.(json.RawMessage); !ok || string(g) != string(e) || text(got) != string(e) {
+					t.Errorf("Se esperaba %s, obtuvo: %s", tt.expected, g)
+				}
+				return
+			}
+			if g, ok := got.(time.Time); ok {
+				if e, ok := tt.expected.(time.Time); !ok || !g.Equal(e) {
+					t.Errorf("Se esperaba %v, obtuvo: %v", tt.expected, got)
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Errorf("Se esperaba %v (%T), obtuvo: %v (%T)", tt.expected, tt.expected, got, got)
+			}
+		})
+	}
+}
+
+// TestColumnName valida la numeración de columnas de Excel
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d): se esperaba %q, obtuvo: %q", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package export
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/wfrscltech/vulcano/fn"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Tipos de columna cuyo contenido es binario y no texto
+var binaryTypes = []string{
+	"BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "IMAGE", "TIMESTAMP_BINARY",
+}
+
+// Tipos de columna decimales que algunos drivers entregan como texto
+var decimalTypes = []string{"DECIMAL", "NUMERIC", "MONEY", "SMALLMONEY"}
+
+// Tipos de columna que solo contienen la fecha
+var dateTypes = []string{"DATE"}
+
+// Tipos de columna con fecha y hora, que algunos drivers (SQLite, MySQL sin parseTime) entregan como texto
+var timestampTypes = []string{
+	"DATETIME", "DATETIME2", "SMALLDATETIME", "DATETIMEOFFSET", "TIMESTAMP", "TIMESTAMPTZ",
+}
+
+// Formatos con que se interpreta una fecha entregada como texto
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999", time.DateOnly}
+
+// date es el valor de una columna DATE: se exporta como `2006-01-02` en los formatos de texto y como fecha
+// serial sin hora en XLSX
+type date time.Time
+
+func (d date) String() string {
+	return time.Time(d).Format(time.DateOnly)
+}
+
+func (d date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// normalize convierte el valor entregado por el driver a un tipo básico usando la descripción de la
+// columna: texto, número, booleano, fecha, binario, JSON o nil. Los NaN e infinitos se convierten en nil,
+// que JSON no puede representar
+func normalize(ct database.ColumnType, v any) any {
+	switch x := v.(type) {
+	case nil, bool, int64, int32, int16, int8, int, uint64, uint32, uint16, uint8, json.Number:
+		return v
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil
+		}
+		return v
+	case float32:
+		return normalize(ct, float64(x))
+	case string:
+		if fn.In(ct.DatabaseType, dateTypes...) || fn.In(ct.DatabaseType, timestampTypes...) {
+			if t, ok := parseTime(x); ok {
+				return normalize(ct, t)
+			}
+		}
+		return v
+	case time.Time:
+		if fn.In(ct.DatabaseType, dateTypes...) {
+			return date(x)
+		}
+		return x
+	case []byte:
+		switch {
+		case ct.DatabaseType == "UNIQUEIDENTIFIER" && len(x) == 16:
+			return mssqlUUID(x)
+		case fn.In(ct.DatabaseType, decimalTypes...):
+			return number(string(x))
+		case fn.In(ct.DatabaseType, binaryTypes...):
+			return x
+		default:
+			return normalize(ct, string(x))
+		}
+	case [16]byte:
+		return uuid(x[:])
+	case driver.Valuer:
+		dv, err := x.Value()
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		if s, ok := dv.(string); ok && fn.In(ct.DatabaseType, decimalTypes...) {
+			return number(s)
+		}
+		return normalize(ct, dv)
+	case map[string]any, []any:
+		return rawJSON(v)
+	default:
+		if k := reflect.ValueOf(v).Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+			return rawJSON(v)
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// rawJSON convierte los valores JSON/JSONB (map[string]any, []any) y los arreglos ([]string, []int64...)
+// a JSON, que se exporta tal cual en JSON y como texto en CSV y XLSX
+func rawJSON(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return json.RawMessage(b)
+}
+
+// parseTime interpreta una fecha entregada como texto
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// number devuelve un json.Number si el texto es un número válido, o el texto en caso contrario (NaN)
+func number(s string) any {
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return s
+	}
+	return json.Number(s)
+}
+
+// uuid formatea 16 bytes en la representación canónica de un UUID
+func uuid(b []byte) string {
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// mssqlUUID formatea un UNIQUEIDENTIFIER de SQL Server, cuyos tres primeros grupos vienen en little-endian
+func mssqlUUID(b []byte) string {
+	u := []byte{b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6]}
+	return uuid(append(u, b[8:]...))
+}
+
+// text devuelve la representación textual de un valor normalizado, usada por CSV y XLSX
+func text(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case date:
+		return x.String()
+	case json.RawMessage:
+		return string(x)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// jsonWriter escribe un arreglo JSON de objetos o, con `lines`, un objeto por línea (NDJSON)
+type jsonWriter struct {
+	w     *bufio.Writer
+	lines bool
+	keys  [][]byte
+	first bool
+}
+
+func (j *jsonWriter) begin(columns []database.ColumnType) error {
+	j.keys = make([][]byte, len(columns))
+	for i, c := range columns {
+		k, err := json.Marshal(c.Name)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = append(k, ':')
+	}
+
+	j.first = true
+	if !j.lines {
+		return j.w.WriteByte('[')
+	}
+	return nil
+}
+
+func (j *jsonWriter) row(values []any) error {
+	if !j.lines && !j.first {
+		j.w.WriteByte(',')
+	}
+	j.first = false
+
+	j.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		j.w.Write(j.keys[i])
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := j.w.Write(b); err != nil {
+			return err
+		}
+	}
+	j.w.WriteByte('}')
+
+	if j.lines {
+		return j.w.WriteByte('\n')
+	}
+	return nil
+}
+
+func (j *jsonWriter) end() error {
+	if !j.lines {
+		return j.w.WriteByte(']')
+	}
+	return nil
+}
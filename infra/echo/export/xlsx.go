@@ -0,0 +1,147 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// Partes fijas del paquete OOXML de un libro con una sola hoja
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	// Estilos: 0 general, 1 fecha y hora (formato 22), 2 encabezado en negrita, 3 fecha (formato 14)
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="4"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs></styleSheet>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// Fecha base de las fechas seriales de Excel (sistema 1900)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxWriter escribe un libro XLSX con una hoja cuyas filas se transmiten a medida que se generan. Las
+// cadenas se escriben en línea (inlineStr) para no mantener una tabla de cadenas compartidas en memoria
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	name  string
+	line  int
+}
+
+func newXLSXWriter(w *bufio.Writer, sheet string) *xlsxWriter {
+	return &xlsxWriter{zw: zip.NewWriter(w), name: sheet}
+}
+
+func (x *xlsxWriter) begin(columns []database.ColumnType) error {
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(x.name))
+
+	parts := []struct{ path, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, p := range parts {
+		f, err := x.zw.Create(p.path)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = sheet
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return err
+	}
+
+	header := make([]any, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	return x.write(header, 2)
+}
+
+func (x *xlsxWriter) row(values []any) error {
+	return x.write(values, 0)
+}
+
+// write escribe una fila; `style` se aplica a las celdas de texto (encabezado)
+func (x *xlsxWriter) write(values []any, style int) error {
+	x.line++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, x.line)
+	for i, v := range values {
+		ref := columnName(i) + strconv.Itoa(x.line)
+		switch t := v.(type) {
+		case nil:
+			continue
+		case bool:
+			fmt.Fprintf(&b, `<c r="%s" t="b"><v>%s</v></c>`, ref, map[bool]string{true: "1", false: "0"}[t])
+		case int64, int32, int16, int8, int, uint64, uint32, uint16, uint8, float64, float32, json.Number:
+			fmt.Fprintf(&b, `<c r="%s"><v>%v</v></c>`, ref, t)
+		case time.Time:
+			fmt.Fprintf(&b, `<c r="%s" s="1"><v>%s</v></c>`, ref, strconv.FormatFloat(serial(t), 'f', -1, 64))
+		case date:
+			fmt.Fprintf(&b, `<c r="%s" s="3"><v>%d</v></c>`, ref, int64(serial(time.Time(t))))
+		default:
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"`, ref)
+			if style > 0 {
+				fmt.Fprintf(&b, ` s="%d"`, style)
+			}
+			b.WriteString(`><is><t xml:space="preserve">`)
+			xml.EscapeText(&b, []byte(text(v)))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+
+	_, err := io.WriteString(x.sheet, b.String())
+	return err
+}
+
+// serial devuelve la fecha serial de Excel. Excel no maneja zonas horarias: se usa la hora local del valor
+func serial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+func (x *xlsxWriter) end() error {
+	if _, err := io.WriteString(x.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+// columnName devuelve el nombre de la columna de Excel para el índice `i` (base 0): A, B, ..., Z, AA, ...
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
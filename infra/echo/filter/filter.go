@@ -0,0 +1,361 @@
+// Package filter interpreta expresiones de filtrado y ordenamiento de los listados a partir de los
+// parámetros de la petición HTTP (ej. `?filter=status:eq:active,total:gt:100&sort=-created_at`) y las
+// convierte en fragmentos SQL parametrizados. Solo se aceptan los campos declarados por el endpoint.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/fn"
+)
+
+// Cantidad máxima de condiciones por defecto
+const DefaultMaxConditions = 10
+
+// Separadores de la sintaxis de filtrado
+const (
+	conditionSep = ","
+	partSep      = ":"
+	listSep      = "|"
+)
+
+// columnRe valida los nombres de las columnas, que se interpolan en la consulta. Admite el prefijo de la
+// tabla o alias (ej. `p.status`)
+var columnRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*\.)?[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Type define el tipo de dato de un campo filtrable
+type Type int
+
+const (
+	String Type = iota
+	Integer
+	Number
+	Bool
+	// Fecha `2006-01-02` o fecha y hora RFC 3339
+	Date
+	UUID
+)
+
+// Op define un operador de filtrado
+type Op string
+
+const (
+	Eq  Op = "eq"
+	Ne  Op = "ne"
+	Gt  Op = "gt"
+	Gte Op = "gte"
+	Lt  Op = "lt"
+	Lte Op = "lte"
+	// Contiene el texto indicado, sin distinguir comodines
+	Like Op = "like"
+	// Pertenece a una lista de valores separados por `|` (ej. `status:in:active|pending`)
+	In Op = "in"
+	// Es nulo (`true`) o no nulo (`false`)
+	Null Op = "null"
+)
+
+// Operadores permitidos por defecto según el tipo del campo
+var defaultOps = map[Type][]Op{
+	String:  {Eq, Ne, Like, In, Null},
+	Integer: {Eq, Ne, Gt, Gte, Lt, Lte, In, Null},
+	Number:  {Eq, Ne, Gt, Gte, Lt, Lte, In, Null},
+	Bool:    {Eq, Ne, Null},
+	Date:    {Eq, Ne, Gt, Gte, Lt, Lte, Null},
+	UUID:    {Eq, Ne, In, Null},
+}
+
+// Field declara un campo que el endpoint permite filtrar u ordenar
+type Field struct {
+	// Nombre público del campo en la petición
+	Name string
+	// Columna SQL; si se omite se usa Name
+	Column string
+	// Tipo de dato, define la validación y conversión de los valores
+	Type Type
+	// Operadores permitidos; si se omite se usan los del tipo
+	Ops []Op
+	// Permite ordenar por el campo
+	Sortable bool
+	// Validación adicional de los valores de tipo String con fn.ValidateRegexp (ej. `AlphaNumeric`, `UUID`)
+	Pattern string
+}
+
+func (f Field) column() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.Name
+}
+
+func (f Field) allows(op Op) bool {
+	if f.Ops != nil {
+		return slices.Contains(f.Ops, op)
+	}
+	return slices.Contains(defaultOps[f.Type], op)
+}
+
+// Condition es una condición de filtrado ya validada
+type Condition struct {
+	Field Field
+	Op    Op
+	// Valores convertidos al tipo del campo; uno solo salvo en `in`, ninguno en `null`
+	Values []any
+	// En `null`, indica si se buscan los nulos (true) o los no nulos (false)
+	IsNull bool
+}
+
+// Sort es un criterio de ordenamiento ya validado
+type Sort struct {
+	Field Field
+	Desc  bool
+}
+
+// Params contiene el filtrado y ordenamiento de una petición
+type Params struct {
+	Conditions []Condition
+	Sort       []Sort
+}
+
+// Option configura la lectura de los parámetros de filtrado
+type Option func(*options)
+
+type options struct {
+	maxConditions int
+	defaultSort   string
+}
+
+// WithMaxConditions cambia la cantidad máxima de condiciones admitidas en `filter`
+func WithMaxConditions(n int) Option {
+	return func(o *options) {
+		o.maxConditions = n
+	}
+}
+
+// WithDefaultSort define el ordenamiento usado cuando la petición no indica `sort`, con la misma sintaxis
+// del parámetro (ej. `-created_at,id`)
+func WithDefaultSort(sort string) Option {
+	return func(o *options) {
+		o.defaultSort = sort
+	}
+}
+
+// Parse lee los parámetros `filter` y `sort` de la petición y los valida contra los campos declarados.
+// Los campos desconocidos, operadores no permitidos y valores inválidos se devuelven como errores
+// mistake.Invalid cuya ruta es el nombre del campo
+func Parse(c echo.Context, fields []Field, opts ...Option) (Params, error) {
+	o := options{maxConditions: DefaultMaxConditions}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var p Params
+	if err := validateFields(fields); err != nil {
+		return p, err
+	}
+
+	if v := strings.TrimSpace(c.QueryParam("filter")); v != "" {
+		conds, err := parseFilter(v, fields, o.maxConditions)
+		if err != nil {
+			return p, err
+		}
+		p.Conditions = conds
+	}
+
+	sort := strings.TrimSpace(c.QueryParam("sort"))
+	if sort == "" {
+		sort = o.defaultSort
+	}
+	if sort != "" {
+		s, err := parseSort(sort, fields)
+		if err != nil {
+			return p, err
+		}
+		p.Sort = s
+	}
+
+	return p, nil
+}
+
+func parseFilter(v string, fields []Field, maxConditions int) ([]Condition, error) {
+	exprs := strings.Split(v, conditionSep)
+	if len(exprs) > maxConditions {
+		return nil, invalid("filter", fmt.Sprintf("el parámetro `filter` admite como máximo %d condiciones", maxConditions), errors.New("demasiadas condiciones"))
+	}
+
+	conds := make([]Condition, 0, len(exprs))
+	for _, expr := range exprs {
+		parts := strings.SplitN(strings.TrimSpace(expr), partSep, 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, invalid("filter", fmt.Sprintf("la condición `%s` no tiene el formato campo:operador:valor", expr), errors.New("condición mal formada"))
+		}
+
+		name, op, value := parts[0], Op(strings.ToLower(parts[1])), parts[2]
+		f, ok := lookup(fields, name)
+		if !ok {
+			return nil, invalid(name, fmt.Sprintf("no se puede filtrar por el campo `%s`", name), errors.New("campo no permitido"))
+		}
+		if !f.allows(op) {
+			return nil, invalid(name, fmt.Sprintf("el campo `%s` no admite el operador `%s`", name, op), errors.New("operador no permitido"))
+		}
+
+		cond, err := condition(f, op, value)
+		if err != nil {
+			return nil, invalid(name, fmt.Sprintf("el valor `%s` no es válido para el campo `%s`", value, name), err)
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func condition(f Field, op Op, value string) (Condition, error) {
+	cond := Condition{Field: f, Op: op}
+
+	switch op {
+	case Null:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return cond, errors.New("debe ser true o false")
+		}
+		cond.IsNull = b
+		return cond, nil
+
+	case In:
+		list := strings.Split(value, listSep)
+		if err := validateList(f.Type, strings.Join(list, ",")); err != nil {
+			return cond, err
+		}
+		for _, item := range list {
+			v, err := convert(f, strings.TrimSpace(item))
+			if err != nil {
+				return cond, err
+			}
+			cond.Values = append(cond.Values, v)
+		}
+		return cond, nil
+
+	case Like:
+		if strings.TrimSpace(value) == "" {
+			return cond, errors.New("no puede estar vacío")
+		}
+		cond.Values = []any{"%" + escapeLike(value) + "%"}
+		return cond, nil
+	}
+
+	v, err := convert(f, value)
+	if err != nil {
+		return cond, err
+	}
+	cond.Values = []any{v}
+	return cond, nil
+}
+
+// validateList valida las listas de `in` con los validadores de listas de fn
+func validateList(t Type, list string) error {
+	if t == Integer {
+		return fn.ValidateIntegerList(list)
+	}
+	return fn.ValidateStringList(list)
+}
+
+// convert valida `value` y lo convierte al tipo Go del campo
+func convert(f Field, value string) (any, error) {
+	switch f.Type {
+	case Integer:
+		if err := fn.ValidateIntegerList(value); err != nil {
+			return nil, err
+		}
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+
+	case Number:
+		return strconv.ParseFloat(value, 64)
+
+	case Bool:
+		return strconv.ParseBool(value)
+
+	case Date:
+		if t, err := time.Parse(time.DateOnly, value); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.RFC3339, value)
+
+	case UUID:
+		if err := fn.ValidateRegexp("UUID", strings.ToLower(value), "debe ser un UUID"); err != nil {
+			return nil, err
+		}
+		return strings.ToLower(value), nil
+	}
+
+	if f.Pattern != "" {
+		if err := fn.ValidateRegexp(f.Pattern, value, fmt.Sprintf("no cumple el formato %s", f.Pattern)); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func parseSort(v string, fields []Field) ([]Sort, error) {
+	var sorts []Sort
+	for item := range strings.SplitSeq(v, conditionSep) {
+		item = strings.TrimSpace(item)
+		s := Sort{}
+		switch {
+		case strings.HasPrefix(item, "-"):
+			s.Desc = true
+			item = item[1:]
+		case strings.HasPrefix(item, "+"):
+			item = item[1:]
+		}
+		if item == "" {
+			return nil, invalid("sort", "el parámetro `sort` contiene un campo vacío", errors.New("campo vacío"))
+		}
+
+		f, ok := lookup(fields, item)
+		if !ok || !f.Sortable {
+			return nil, invalid(item, fmt.Sprintf("no se puede ordenar por el campo `%s`", item), errors.New("campo no permitido"))
+		}
+		if slices.ContainsFunc(sorts, func(s Sort) bool { return s.Field.Name == f.Name }) {
+			return nil, invalid(item, fmt.Sprintf("el campo `%s` se repite en el parámetro `sort`", item), errors.New("campo repetido"))
+		}
+		s.Field = f
+		sorts = append(sorts, s)
+	}
+	return sorts, nil
+}
+
+func lookup(fields []Field, name string) (Field, bool) {
+	i := slices.IndexFunc(fields, func(f Field) bool { return f.Name == name })
+	if i < 0 {
+		return Field{}, false
+	}
+	return fields[i], true
+}
+
+func validateFields(fields []Field) error {
+	for _, f := range fields {
+		if !columnRe.MatchString(f.column()) {
+			return fmt.Errorf("filtro: el nombre de columna `%s` no es válido", f.column())
+		}
+		if _, ok := defaultOps[f.Type]; !ok {
+			return fmt.Errorf("filtro: el tipo del campo `%s` no es válido", f.Name)
+		}
+	}
+	return nil
+}
+
+// escapeLike escapa los comodines de LIKE para buscar el texto literal; se usa con `ESCAPE '!'`. No se usa
+// la barra invertida porque MySQL/MariaDB la interpretan como escape dentro del literal `'\'`
+func escapeLike(s string) string {
+	return strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`, `[`, `![`).Replace(s)
+}
+
+func invalid(field, msg string, err error) error {
+	return mistake.New(mistake.Invalid, msg, err, field)
+}
@@ -0,0 +1,190 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+var campos = []Field{
+	{Name: "status", Type: String, Sortable: true},
+	{Name: "total", Type: Number},
+	{Name: "cantidad", Column: "qty", Type: Integer},
+	{Name: "activo", Type: Bool},
+	{Name: "created_at", Type: Date, Sortable: true},
+	{Name: "id", Type: UUID, Sortable: true},
+	{Name: "codigo", Type: String, Pattern: "AlphaNumeric", Ops: []Op{Eq}},
+}
+
+func parse(t *testing.T, query url.Values, opts ...Option) (Params, error) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	return Parse(c, campos, opts...)
+}
+
+func TestParse(t *testing.T) {
+	p, err := parse(t, url.Values{
+		"filter": {"status:eq:active,total:gt:100.5,cantidad:in:1|2|3,created_at:gte:2024-01-31,activo:null:false"},
+		"sort":   {"-created_at,id"},
+	})
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+
+	if len(p.Conditions) != 5 {
+		t.Fatalf("Se esperaban 5 condiciones, pero obtuvo %d", len(p.Conditions))
+	}
+	want := [][]any{{"active"}, {100.5}, {int64(1), int64(2), int64(3)}, {time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}, nil}
+	for i, c := range p.Conditions {
+		if !reflect.DeepEqual(c.Values, want[i]) {
+			t.Errorf("Condición %d: se esperaba %v, pero obtuvo %v", i, want[i], c.Values)
+		}
+	}
+	if p.Conditions[4].IsNull {
+		t.Error("Se esperaba IS NOT NULL en `activo`")
+	}
+
+	if got := p.OrderBy(); got != "created_at DESC, id ASC" {
+		t.Errorf("Ordenamiento inesperado: %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		sort   string
+		field  string
+	}{
+		{name: "campo desconocido", filter: "password:eq:x", field: "password"},
+		{name: "operador no permitido", filter: "activo:gt:true", field: "activo"},
+		{name: "operador desconocido", filter: "status:regex:x", field: "status"},
+		{name: "entero inválido", filter: "cantidad:eq:1e3", field: "cantidad"},
+		{name: "lista inválida", filter: "cantidad:in:1||2", field: "cantidad"},
+		{name: "fecha inválida", filter: "created_at:lt:ayer", field: "created_at"},
+		{name: "uuid inválido", filter: "id:eq:1234", field: "id"},
+		{name: "patrón", filter: "codigo:eq:no-es-alfanumérico", field: "codigo"},
+		{name: "null inválido", filter: "status:null:quizas", field: "status"},
+		{name: "mal formado", filter: "status-eq-active", field: "status-eq-active"},
+		{name: "orden no permitido", sort: "total", field: "total"},
+		{name: "orden repetido", sort: "id,-id", field: "id"},
+		{name: "orden vacío", sort: "id,", field: "sort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(t, url.Values{"filter": {tt.filter}, "sort": {tt.sort}})
+
+			var m *mistake.Mistake
+			if !errors.As(err, &m) || m.Code() != http.StatusBadRequest {
+				t.Fatalf("Se esperaba un error mistake.Invalid, pero obtuvo: %v", err)
+			}
+			if !strings.Contains(m.Error(), tt.field) {
+				t.Errorf("Se esperaba que el error nombrara `%s`, pero obtuvo: %v", tt.field, m)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	if _, err := parse(t, url.Values{"filter": {"status:eq:a,status:ne:b"}}, WithMaxConditions(1)); err == nil {
+		t.Error("Se esperaba error por exceso de condiciones")
+	}
+
+	p, err := parse(t, url.Values{}, WithDefaultSort("-id"))
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if got := p.OrderBy(); got != "id DESC" {
+		t.Errorf("Ordenamiento por defecto inesperado: %s", got)
+	}
+}
+
+func TestParseInvalidColumn(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	_, err := Parse(c, []Field{{Name: "x", Column: "x; DROP TABLE t"}})
+	if err == nil {
+		t.Error("Se esperaba error por columna inválida")
+	}
+}
+
+func TestWhere(t *testing.T) {
+	p, err := parse(t, url.Values{"filter": {"status:like:50%_off,cantidad:in:1|2,total:lte:10,id:null:true"}})
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+
+	tests := []struct {
+		typo string
+		want string
+	}{
+		{config.DatabaseTypePostgres, `status ILIKE $2 ESCAPE '!' AND qty IN ($3, $4) AND total <= $5 AND id IS NULL`},
+		{config.DatabaseTypeMssql, `status LIKE @p2 ESCAPE '!' AND qty IN (@p3, @p4) AND total <= @p5 AND id IS NULL`},
+		{config.DatabaseTypeMysql, `status LIKE ? ESCAPE '!' AND qty IN (?, ?) AND total <= ? AND id IS NULL`},
+	}
+	for _, tt := range tests {
+		where, args := p.Where(tt.typo, []any{"base"})
+		if where != tt.want {
+			t.Errorf("%s: se esperaba\n%s\npero obtuvo\n%s", tt.typo, tt.want, where)
+		}
+		want := []any{"base", `%50!%!_off%`, int64(1), int64(2), 10.0}
+		if !reflect.DeepEqual(args, want) {
+			t.Errorf("%s: argumentos inesperados %v", tt.typo, args)
+		}
+	}
+
+	if where, args := (Params{}).Where(config.DatabaseTypePostgres, nil); where != "" || len(args) != 0 {
+		t.Errorf("Se esperaba un fragmento vacío, pero obtuvo %q %v", where, args)
+	}
+}
+
+func TestApplySQLite(t *testing.T) {
+	if err := database.New(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite}); err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	db := database.GetDatabase()
+	t.Cleanup(db.Close)
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE pedidos (status TEXT, total REAL, qty INTEGER)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+	for _, r := range [][]any{{"active", 50.0, 1}, {"active", 150.0, 2}, {"closed", 500.0, 3}, {"100%", 1.0, 4}} {
+		if _, err := db.Exec(ctx, "INSERT INTO pedidos VALUES (?, ?, ?)", r...); err != nil {
+			t.Fatalf("Error al insertar: %v", err)
+		}
+	}
+
+	p, err := parse(t, url.Values{"filter": {"status:eq:active,total:gt:100"}})
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	sql, args := p.Apply(config.DatabaseTypeSqlite, "SELECT qty FROM pedidos", nil)
+	var qty int
+	if err := db.QueryRow(ctx, sql, args...).Scan(&qty); err != nil || qty != 2 {
+		t.Errorf("Se esperaba el pedido 2, pero obtuvo %d (%v)", qty, err)
+	}
+
+	p, err = parse(t, url.Values{"filter": {"status:like:%"}})
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	sql, args = p.Apply(config.DatabaseTypeSqlite, "SELECT COUNT(*) FROM pedidos", nil)
+	var n int
+	if err := db.QueryRow(ctx, sql, args...).Scan(&n); err != nil || n != 1 {
+		t.Errorf("Se esperaba que `%%` se buscara como literal, pero obtuvo %d registros (%v)", n, err)
+	}
+}
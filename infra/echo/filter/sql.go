@@ -0,0 +1,106 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/echo/pagination"
+)
+
+// Operadores SQL de comparación
+var sqlOps = map[Op]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// Where devuelve las condiciones unidas con AND, sin la palabra WHERE, y los argumentos de la consulta.
+// Los valores se agregan a continuación de `args`, con los marcadores del dialecto indicado por `typo`
+// (config.DatabaseConfig.Typo), de modo que el fragmento se puede anexar a una consulta con parámetros
+// propios. Devuelve una cadena vacía si no hay condiciones.
+//
+// En PostgreSQL `like` usa ILIKE para no distinguir mayúsculas, igual que la intercalación habitual de
+// SQL Server
+func (p Params) Where(typo string, args []any) (string, []any) {
+	args = slices.Clone(args)
+	if len(p.Conditions) == 0 {
+		return "", args
+	}
+
+	parts := make([]string, len(p.Conditions))
+	for i, c := range p.Conditions {
+		col := c.Field.column()
+
+		switch c.Op {
+		case Null:
+			if c.IsNull {
+				parts[i] = col + " IS NULL"
+			} else {
+				parts[i] = col + " IS NOT NULL"
+			}
+
+		case In:
+			marks := make([]string, len(c.Values))
+			for j, v := range c.Values {
+				args = append(args, v)
+				marks[j] = database.Placeholder(typo, len(args))
+			}
+			parts[i] = fmt.Sprintf("%s IN (%s)", col, strings.Join(marks, ", "))
+
+		case Like:
+			args = append(args, c.Values[0])
+			like := "LIKE"
+			if typo == config.DatabaseTypePostgres {
+				like = "ILIKE"
+			}
+			parts[i] = fmt.Sprintf(`%s %s %s ESCAPE '!'`, col, like, database.Placeholder(typo, len(args)))
+
+		default:
+			args = append(args, c.Values[0])
+			parts[i] = fmt.Sprintf("%s %s %s", col, sqlOps[c.Op], database.Placeholder(typo, len(args)))
+		}
+	}
+
+	return strings.Join(parts, " AND "), args
+}
+
+// OrderBy devuelve el ordenamiento sin las palabras ORDER BY (ej. `created_at DESC, id ASC`). Devuelve una
+// cadena vacía si no hay criterios de ordenamiento
+func (p Params) OrderBy() string {
+	parts := make([]string, len(p.Sort))
+	for i, s := range p.Sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		parts[i] = s.Field.column() + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Keys devuelve el ordenamiento como llaves de pagination.Query. Como la paginación usa la consulta base
+// como subconsulta, las columnas de los campos ordenables deben ser nombres de columnas del resultado
+func (p Params) Keys() []pagination.Key {
+	keys := make([]pagination.Key, len(p.Sort))
+	for i, s := range p.Sort {
+		keys[i] = pagination.Key{Column: s.Field.column(), Desc: s.Desc}
+	}
+	return keys
+}
+
+// Apply agrega las condiciones a la consulta base como `WHERE ...`; la consulta base no debe tener WHERE.
+// No agrega el ordenamiento para que el resultado sirva como consulta base de pagination.Query, junto con
+// Keys
+func (p Params) Apply(typo, base string, args []any) (string, []any) {
+	where, args := p.Where(typo, args)
+	if where == "" {
+		return base, args
+	}
+	return base + " WHERE " + where, args
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/infra/database"
 )
 
 type healthHandler struct {
@@ -25,8 +26,10 @@ func NewHealthHandler(version, buildTime, commitHash string) *healthHandler {
 
 // @Description	Define la respuesta de la API de healthcheck
 type HealthResponse struct {
-	// Estado del servidor
+	// Estado del servidor: `ok` o `not ready` mientras la base de datos no está disponible
 	Status string `json:"status"      example:"ok"`
+	// Detalle del estado de la base de datos cuando no está disponible
+	Database string `json:"database,omitempty" example:"la base de datos todavía no está disponible"`
 	// Versión de la aplicación servidor
 	Version string `json:"version"     example:"0.1.0"`
 	// Hora de compilación de la aplicación
@@ -40,21 +43,26 @@ type HealthResponse struct {
 }
 
 // @Summary		Validación de funcionamiento
-// @Description	Endpoint de validación de la API, indica si el servidor está en funcionamiento
+// @Description	Endpoint de validación de la API, indica si el servidor está en funcionamiento y si la base de datos está disponible
 // @Tags			Monitoring
 // @Produce		json
 // @Success		200	{object}	HealthResponse
+// @Failure		503	{object}	HealthResponse
 // @Router			/health [get]
 func (h *healthHandler) Healthcheck(c echo.Context) error {
-	return c.JSON(
-		http.StatusOK,
-		HealthResponse{
-			Status:     "ok",
-			Version:    h.version,
-			BuildTime:  h.buildTime,
-			CommitHash: h.commitHash,
-			Time:       time.Now().Format("2006-01-02 15:04:05"),
-			Uptime:     time.Since(time.Unix(h.start, 0)).String(),
-		},
-	)
+	code, res := http.StatusOK, HealthResponse{
+		Status:     "ok",
+		Version:    h.version,
+		BuildTime:  h.buildTime,
+		CommitHash: h.commitHash,
+		Time:       time.Now().Format("2006-01-02 15:04:05"),
+		Uptime:     time.Since(time.Unix(h.start, 0)).String(),
+	}
+
+	// Con conexión diferida el servicio responde antes de que la base de datos esté disponible
+	if err := database.Ready(); err != nil {
+		code, res.Status, res.Database = http.StatusServiceUnavailable, "not ready", err.Error()
+	}
+
+	return c.JSON(code, res)
 }
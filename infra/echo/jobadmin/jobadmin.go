@@ -0,0 +1,149 @@
+// Package jobadmin expone la API de administración de la cola de trabajos (ver infra/database/jobs):
+// listado y detalle de los trabajos, reintento de los trabajos `dead` o `cancelled` y cancelación de los
+// pendientes. Las rutas se registran en un grupo que la aplicación debe proteger con su autenticación:
+//
+//	admin := e.Group("/admin", auth)
+//	jobadmin.Register(admin, queue)
+package jobadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database/jobs"
+	"github.com/wfrscltech/vulcano/infra/echo/pagination"
+)
+
+type handler struct {
+	queue *jobs.Queue
+}
+
+// Register registra las rutas `/jobs` en el grupo indicado
+func Register(g *echo.Group, q *jobs.Queue) {
+	h := &handler{queue: q}
+	g.GET("/jobs", h.List)
+	g.GET("/jobs/:id", h.Get)
+	g.POST("/jobs/:id/retry", h.Retry)
+	g.POST("/jobs/:id/cancel", h.Cancel)
+}
+
+// @Summary		Listado de trabajos
+// @Description	Lista los trabajos en segundo plano, del más reciente al más antiguo
+// @Tags			Jobs
+// @Produce		json
+// @Param			status	query		string	false	"Estado del trabajo"	Enums(pending, running, done, dead, cancelled)
+// @Param			kind	query		string	false	"Tipo de trabajo"
+// @Param			page	query		int		false	"Número de página"
+// @Param			size	query		int		false	"Tamaño de página"
+// @Success		200		{object}	pagination.Page[jobs.Job]
+// @Failure		400		{object}	middleware.ClientError
+// @Router			/jobs [get]
+func (h *handler) List(c echo.Context) error {
+	p, err := pagination.Parse(c)
+	if err != nil {
+		return err
+	}
+
+	status := jobs.Status(c.QueryParam("status"))
+	switch status {
+	case "", jobs.StatusPending, jobs.StatusRunning, jobs.StatusDone, jobs.StatusDead, jobs.StatusCancelled:
+	default:
+		return mistake.New(mistake.Invalid, fmt.Sprintf("el estado `%s` no es válido", status), errors.New("estado no válido"), "status")
+	}
+
+	items, err := h.queue.List(c.Request().Context(), jobs.Filter{
+		Status: status,
+		Kind:   c.QueryParam("kind"),
+		Limit:  p.Size,
+		Offset: p.Offset(),
+	})
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []jobs.Job{}
+	}
+
+	return c.JSON(http.StatusOK, pagination.Page[jobs.Job]{Items: items, Page: p.Page, Size: p.Size})
+}
+
+// @Summary		Detalle de un trabajo
+// @Description	Devuelve un trabajo en segundo plano con su estado, intentos y último error
+// @Tags			Jobs
+// @Produce		json
+// @Param			id	path		int	true	"Identificador del trabajo"
+// @Success		200	{object}	jobs.Job
+// @Failure		400	{object}	middleware.ClientError
+// @Failure		404	{object}	middleware.ClientError
+// @Router			/jobs/{id} [get]
+func (h *handler) Get(c echo.Context) error {
+	id, err := jobID(c)
+	if err != nil {
+		return err
+	}
+
+	j, err := h.queue.Get(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, j)
+}
+
+// @Summary		Reintento de un trabajo
+// @Description	Vuelve a encolar para ejecución inmediata un trabajo `dead` o `cancelled`, con sus intentos en cero. Responde 409 si otro trabajo pendiente usa la misma llave única
+// @Tags			Jobs
+// @Produce		json
+// @Param			id	path		int	true	"Identificador del trabajo"
+// @Success		200	{object}	jobs.Job
+// @Failure		400	{object}	middleware.ClientError
+// @Failure		404	{object}	middleware.ClientError
+// @Failure		409	{object}	middleware.ClientError
+// @Router			/jobs/{id}/retry [post]
+func (h *handler) Retry(c echo.Context) error {
+	return h.transition(c, h.queue.Retry)
+}
+
+// @Summary		Cancelación de un trabajo
+// @Description	Cancela un trabajo pendiente; los trabajos en curso no se pueden cancelar
+// @Tags			Jobs
+// @Produce		json
+// @Param			id	path		int	true	"Identificador del trabajo"
+// @Success		200	{object}	jobs.Job
+// @Failure		400	{object}	middleware.ClientError
+// @Failure		404	{object}	middleware.ClientError
+// @Router			/jobs/{id}/cancel [post]
+func (h *handler) Cancel(c echo.Context) error {
+	return h.transition(c, h.queue.Cancel)
+}
+
+// transition aplica el cambio de estado y responde con el trabajo actualizado
+func (h *handler) transition(c echo.Context, apply func(ctx context.Context, id int64) error) error {
+	id, err := jobID(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := apply(ctx, id); err != nil {
+		return err
+	}
+	j, err := h.queue.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, j)
+}
+
+func jobID(c echo.Context) (int64, error) {
+	v := c.Param("id")
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 1 {
+		return 0, mistake.New(mistake.Invalid, fmt.Sprintf("el identificador de trabajo `%s` no es válido", v), errors.New("identificador no válido"), "id")
+	}
+	return id, nil
+}
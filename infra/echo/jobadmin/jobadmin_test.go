@@ -0,0 +1,81 @@
+package jobadmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/jobs"
+	"github.com/wfrscltech/vulcano/infra/echo/middleware"
+	"github.com/wfrscltech/vulcano/infra/echo/pagination"
+)
+
+func TestRoutes(t *testing.T) {
+	db, err := database.Open(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite})
+	if err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	defer db.Close()
+
+	ctx := database.WithDatabase(context.Background(), db)
+	if _, err := db.Exec(ctx, jobs.SQLiteDDL); err != nil {
+		t.Fatalf("Error al crear la tabla de trabajos: %v", err)
+	}
+	q, _ := jobs.New(config.DatabaseTypeSqlite)
+	a, _ := q.Enqueue(ctx, "reporte", map[string]string{"mes": "2024-01"})
+	q.Enqueue(ctx, "correo", nil)
+
+	e := echo.New()
+	e.Use(middleware.ProblemMiddleware)
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(database.WithDatabase(c.Request().Context(), db)))
+			return next(c)
+		}
+	})
+	Register(e.Group("/admin"), q)
+
+	call := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	rec := call(http.MethodGet, "/admin/jobs?kind=reporte")
+	var page pagination.Page[jobs.Job]
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /jobs = %d %s", rec.Code, rec.Body.String())
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != a || page.Items[0].Status != jobs.StatusPending {
+		t.Errorf("Se esperaba solo el trabajo pendiente %d, obtuvo %+v", a, page.Items)
+	}
+
+	rec = call(http.MethodPost, "/admin/jobs/1/cancel")
+	var j jobs.Job
+	if err := json.Unmarshal(rec.Body.Bytes(), &j); err != nil || rec.Code != http.StatusOK || j.Status != jobs.StatusCancelled {
+		t.Errorf("POST /jobs/1/cancel = %d %s", rec.Code, rec.Body.String())
+	}
+	if rec = call(http.MethodPost, "/admin/jobs/1/retry"); rec.Code != http.StatusOK {
+		t.Errorf("POST /jobs/1/retry = %d %s", rec.Code, rec.Body.String())
+	}
+
+	failures := []struct {
+		method, target string
+		code           int
+	}{
+		{http.MethodGet, "/admin/jobs?status=borrado", http.StatusBadRequest},
+		{http.MethodGet, "/admin/jobs/abc", http.StatusBadRequest},
+		{http.MethodGet, "/admin/jobs/99", http.StatusNotFound},
+		{http.MethodPost, "/admin/jobs/2/retry", http.StatusBadRequest},
+	}
+	for _, f := range failures {
+		if rec := call(f.method, f.target); rec.Code != f.code {
+			t.Errorf("%s %s = %d; se esperaba %d", f.method, f.target, rec.Code, f.code)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	echom "github.com/labstack/echo/v4/middleware"
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// Valores por defecto de la política CORS
+var (
+	DefaultCORSMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}
+	DefaultCORSExposeHeaders = []string{"ETag", HeaderRequestID}
+)
+
+// CORSMiddleware aplica la política CORS de la configuración (ver config.CORSConfig, validada al cargarla).
+// Sin métodos usa DefaultCORSMethods, sin encabezados permitidos acepta los que solicite el navegador y sin
+// encabezados expuestos usa DefaultCORSExposeHeaders, de modo que el cliente pueda leer el `ETag` y el
+// `X-Request-ID`. Los orígenes `https://*.example.com` aceptan cualquier subdominio de `example.com`, pero
+// no el dominio mismo
+func CORSMiddleware(cfg config.CORSConfig) echo.MiddlewareFunc {
+	c := echom.CORSConfig{
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+	if len(c.AllowMethods) == 0 {
+		c.AllowMethods = DefaultCORSMethods
+	}
+	if len(c.ExposeHeaders) == 0 {
+		c.ExposeHeaders = DefaultCORSExposeHeaders
+	}
+
+	if len(cfg.AllowOrigins) == 1 && cfg.AllowOrigins[0] == "*" {
+		c.AllowOrigins = cfg.AllowOrigins
+	} else {
+		c.AllowOriginFunc = originMatcher(cfg.AllowOrigins)
+	}
+
+	return echom.CORSWithConfig(c)
+}
+
+// originMatcher compara el origen de la petición con los orígenes exactos y los comodines de subdominio
+func originMatcher(origins []string) func(origin string) (bool, error) {
+	type wildcard struct{ prefix, suffix string }
+
+	exact := map[string]bool{}
+	var wildcards []wildcard
+	for _, o := range origins {
+		o = strings.ToLower(o)
+		if scheme, host, ok := strings.Cut(o, "://*."); ok {
+			wildcards = append(wildcards, wildcard{prefix: scheme + "://", suffix: "." + host})
+			continue
+		}
+		exact[o] = true
+	}
+
+	return func(origin string) (bool, error) {
+		origin = strings.ToLower(origin)
+		if exact[origin] {
+			return true, nil
+		}
+		for _, w := range wildcards {
+			if !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+				continue
+			}
+			sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+			if sub != "" && strings.Trim(sub, "abcdefghijklmnopqrstuvwxyz0123456789-.") == "" &&
+				!strings.HasPrefix(sub, ".") && !strings.HasSuffix(sub, ".") && !strings.Contains(sub, "..") {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/config"
+)
+
+// TestCORSMiddleware valida la verificación previa con orígenes exactos y comodines de subdominio
+func TestCORSMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(CORSMiddleware(config.CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}))
+	e.DELETE("/", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodDelete)
+		req.Header.Set(echo.HeaderAccessControlRequestHeaders, "If-Match")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, origin := range []string{"https://app.example.com", "https://api.example.org", "https://a.b.example.org", "HTTPS://APP.EXAMPLE.COM"} {
+		h := preflight(origin).Header()
+		if h.Get(echo.HeaderAccessControlAllowOrigin) != origin || h.Get(echo.HeaderAccessControlAllowCredentials) != "true" {
+			t.Errorf("%s: se esperaba el origen permitido con credenciales, obtuvo %v", origin, h)
+		}
+		if h.Get(echo.HeaderAccessControlAllowMethods) != "GET,HEAD,POST,PUT,PATCH,DELETE" || h.Get(echo.HeaderAccessControlAllowHeaders) != "If-Match" {
+			t.Errorf("%s: métodos o encabezados inesperados: %v", origin, h)
+		}
+		if h.Get(echo.HeaderAccessControlMaxAge) != "600" {
+			t.Errorf("%s: se esperaba Max-Age 600, obtuvo %q", origin, h.Get(echo.HeaderAccessControlMaxAge))
+		}
+	}
+
+	for _, origin := range []string{"https://example.org", "http://app.example.com", "https://evil.com", "https://evil.com:1.example.org", "https://app.example.com.evil.com"} {
+		if got := preflight(origin).Header().Get(echo.HeaderAccessControlAllowOrigin); got != "" {
+			t.Errorf("%s: no se esperaba el origen permitido, obtuvo %q", origin, got)
+		}
+	}
+
+	// En la respuesta real se exponen el ETag y el identificador de la petición
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if got := rec.Header().Get(echo.HeaderAccessControlExposeHeaders); got != "ETag,X-Request-Id" {
+		t.Errorf("Encabezados expuestos inesperados: %q", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/logger"
 )
 
 const baseDomain = "https://developer.mozilla.org"
@@ -65,7 +66,8 @@ type ProblemDetails struct {
 	Status int `json:"status"`
 	// Explicación más específica del problema
 	Detail string `json:"detail"`
-	// URI que identifica esta ocurrencia específica del problema
+	// URI que identifica esta ocurrencia específica del problema, con el identificador de la petición
+	// (`#<X-Request-ID>`) si se usa RequestIDMiddleware
 	Instance string `json:"instance"`
 }
 
@@ -106,12 +108,16 @@ func ProblemMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return nil
 		}
 
+		ctx := c.Request().Context()
 		instance := fmt.Sprintf("[%s] %s", c.Request().Method, c.Request().RequestURI)
+		if id, ok := logger.RequestID(ctx); ok {
+			instance += " #" + id
+		}
 
 		var mk *mistake.Mistake
 		if errors.As(err, &mk) {
 			if mk.Code() == http.StatusInternalServerError {
-				slog.Error("Handle Mistake", slog.String("error", err.Error()))
+				slog.ErrorContext(ctx, "Handle Mistake", slog.String("error", err.Error()))
 				return writeProblem(c, mk.Code(), mk.DevError(), instance)
 			}
 
@@ -119,7 +125,7 @@ func ProblemMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		} else {
 			// Si el handler devolvió un *echo.HTTPError, lo convertimos
 			if he, ok := err.(*echo.HTTPError); ok {
-				slog.Error("Handle HTTP Error", slog.String("error", err.Error()))
+				slog.ErrorContext(ctx, "Handle HTTP Error", slog.String("error", err.Error()))
 				if he.Code >= http.StatusInternalServerError {
 					return writeProblem(c, he.Code, he.Message.(string), instance)
 				}
@@ -128,7 +134,7 @@ func ProblemMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 				return c.JSON(he.Code, ClientError{Error: http.StatusText(he.Code), Message: msg})
 			} else {
 				// Error inesperado o no capturado
-				slog.Error("Handle Unknown Error", slog.String("error", err.Error()))
+				slog.ErrorContext(ctx, "Handle Unknown Error", slog.String("error", err.Error()))
 				return writeProblem(c, http.StatusInternalServerError, err.Error(), instance)
 			}
 		}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/logger"
+)
+
+// HeaderRequestID es el encabezado con el identificador de la petición
+const HeaderRequestID = echo.HeaderXRequestID
+
+// requestIDRe limita los identificadores aceptados del cliente, para que no se puedan inyectar
+// caracteres arbitrarios en los logs
+var requestIDRe = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware acepta el identificador de la petición del encabezado `X-Request-ID` o genera uno
+// nuevo si falta o no es válido. Lo guarda en el contexto de la petición (ver logger.RequestID), donde
+// logger.ContextHandler lo agrega a los logs, y lo devuelve en el mismo encabezado de la respuesta.
+// Debe registrarse antes que los demás middlewares para que sus logs lo incluyan
+func RequestIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		id := req.Header.Get(HeaderRequestID)
+		if !requestIDRe.MatchString(id) {
+			id = rand.Text()
+		}
+
+		c.Response().Header().Set(HeaderRequestID, id)
+		c.SetRequest(req.WithContext(logger.WithRequestID(req.Context(), id)))
+		return next(c)
+	}
+}
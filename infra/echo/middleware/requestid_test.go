@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/logger"
+)
+
+// TestRequestIDMiddleware valida que el identificador de la petición llegue a la respuesta, a los logs y a
+// los detalles del problema
+func TestRequestIDMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(logger.NewContextHandler(slog.NewJSONHandler(&logs, nil))))
+	defer slog.SetDefault(prev)
+
+	e := echo.New()
+	e.Use(RequestIDMiddleware)
+	e.Use(ProblemMiddleware)
+	e.GET("/", func(c echo.Context) error {
+		slog.InfoContext(c.Request().Context(), "Procesando")
+		return errors.New("falla inesperada")
+	})
+
+	call := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if id != "" {
+			req.Header.Set(HeaderRequestID, id)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call("abc-123")
+	if got := rec.Header().Get(HeaderRequestID); got != "abc-123" {
+		t.Errorf("Se esperaba el identificador del cliente en la respuesta, obtuvo %q", got)
+	}
+
+	var pd ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &pd); err != nil {
+		t.Fatalf("Respuesta inesperada: %s", rec.Body.String())
+	}
+	if pd.Instance != "[GET] / #abc-123" {
+		t.Errorf("Instance = %q", pd.Instance)
+	}
+
+	// El log del handler y el de ProblemMiddleware incluyen el identificador
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas de log, obtuvo: %s", logs.String())
+	}
+	for _, l := range lines {
+		if !strings.Contains(l, `"request_id":"abc-123"`) {
+			t.Errorf("La línea de log no incluye el identificador: %s", l)
+		}
+	}
+
+	// Sin encabezado, o con uno no válido, se genera uno nuevo
+	for _, id := range []string{"", "abc\n{\"admin\":true}", strings.Repeat("a", 129)} {
+		got := call(id).Header().Get(HeaderRequestID)
+		if got == "" || got == id || !requestIDRe.MatchString(got) {
+			t.Errorf("Con %q se esperaba un identificador generado, obtuvo %q", id, got)
+		}
+	}
+}
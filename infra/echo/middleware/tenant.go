@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/tenant"
+	"github.com/wfrscltech/vulcano/infra/echo/auth"
+)
+
+// TenantResolver obtiene el identificador del tenant de la petición; devuelve una cadena vacía si la
+// petición no lo indica
+type TenantResolver func(c echo.Context) (string, error)
+
+// TenantFromHeader obtiene el tenant del encabezado `name` (ej. `X-Tenant-ID`)
+func TenantFromHeader(name string) TenantResolver {
+	return func(c echo.Context) (string, error) {
+		return strings.TrimSpace(c.Request().Header.Get(name)), nil
+	}
+}
+
+// TenantFromSubdomain obtiene el tenant del primer nivel del host bajo `domain` (ej. `acme` en
+// `acme.erp.example.com` con el dominio `erp.example.com`)
+func TenantFromSubdomain(domain string) TenantResolver {
+	suffix := "." + strings.ToLower(strings.Trim(domain, "."))
+	return func(c echo.Context) (string, error) {
+		host := strings.ToLower(c.Request().Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		sub, ok := strings.CutSuffix(host, suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return "", nil
+		}
+		return sub, nil
+	}
+}
+
+// TenantFromClaim obtiene el tenant del claim `claim` del token autenticado, que debe ser un texto.
+// auth.JWTMiddleware debe ejecutarse antes
+func TenantFromClaim(claim string) TenantResolver {
+	return func(c echo.Context) (string, error) {
+		claims, ok := auth.ClaimsFrom(c.Request().Context())
+		if !ok {
+			return "", nil
+		}
+
+		v, _ := claims.Get(claim)
+		switch v := v.(type) {
+		case nil:
+			return "", nil
+		case string:
+			return v, nil
+		default:
+			return "", fmt.Errorf("el claim `%s` del tenant no es un texto", claim)
+		}
+	}
+}
+
+// TenantMiddleware dirige cada petición a la conexión de su tenant: la obtiene del registro y la deja en
+// el contexto (ver database.WithDatabase), de modo que database.From, database.RunInTx y
+// TransactionMiddleware la usan en lugar de la conexión global. Las peticiones sin tenant se rechazan con
+// mistake.Required, las de tenants desconocidos con mistake.Forbidden y, si la conexión no se puede
+// abrir, con mistake.Unavailable
+func TenantMiddleware(reg *tenant.Registry, resolve TenantResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, err := resolve(c)
+			if err != nil {
+				return mistake.New(mistake.Invalid, "no se pudo identificar el tenant de la petición", err, "tenant")
+			}
+			if id == "" {
+				return mistake.New(mistake.Required, "la petición no indica el tenant", errors.New("tenant no indicado"), "tenant")
+			}
+
+			req := c.Request()
+			db, release, err := reg.Get(req.Context(), id)
+			if errors.Is(err, tenant.ErrUnknownTenant) {
+				return mistake.New(mistake.Forbidden, fmt.Sprintf("el tenant `%s` no existe o no está habilitado", id), err, "tenant")
+			}
+			if err != nil {
+				return mistake.New(mistake.Unavailable, fmt.Sprintf("la base de datos del tenant `%s` no está disponible", id), err, "tenant")
+			}
+			defer release()
+
+			ctx := database.WithDatabase(tenant.WithID(req.Context(), id), db)
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
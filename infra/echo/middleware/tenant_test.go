@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/tenant"
+	"github.com/wfrscltech/vulcano/infra/echo/auth"
+)
+
+// TestTenantMiddleware valida que cada petición use la base de su tenant y que se rechacen las demás
+func TestTenantMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	names := map[string]string{"acme": filepath.Join(dir, "acme.db"), "globex": filepath.Join(dir, "globex.db")}
+	reg := tenant.NewRegistry(tenant.Databases(config.DatabaseConfig{Typo: config.DatabaseTypeSqlite}, names))
+	defer reg.Close()
+
+	e := echo.New()
+	e.Use(ProblemMiddleware)
+	e.Use(TenantMiddleware(reg, TenantFromHeader("X-Tenant-ID")))
+	e.POST("/", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, _ := tenant.ID(ctx)
+		err := database.RunInTx(ctx, func(ctx context.Context) error {
+			if _, err := database.From(ctx).Exec(ctx, "CREATE TABLE IF NOT EXISTS visitas (tenant TEXT)"); err != nil {
+				return err
+			}
+			_, err := database.From(ctx).Exec(ctx, "INSERT INTO visitas VALUES (?)", id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		var n int
+		if err := database.From(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM visitas").Scan(&n); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, n)
+	})
+
+	call := func(tn string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tn != "" {
+			req.Header.Set("X-Tenant-ID", tn)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	call("acme")
+	if rec := call("acme"); rec.Code != http.StatusOK || rec.Body.String() != "2\n" {
+		t.Errorf("acme: se esperaban 2 visitas, obtuvo: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := call("globex"); rec.Code != http.StatusOK || rec.Body.String() != "1\n" {
+		t.Errorf("globex: se esperaba 1 visita, obtuvo: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := call("initech"); rec.Code != http.StatusForbidden {
+		t.Errorf("Se esperaba 403 para un tenant desconocido, obtuvo: %d", rec.Code)
+	}
+	if rec := call(""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Se esperaba 400 sin tenant, obtuvo: %d", rec.Code)
+	}
+}
+
+func TestTenantResolvers(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "http://acme.erp.example.com:8080/", nil), httptest.NewRecorder())
+
+	if id, _ := TenantFromSubdomain("erp.example.com")(c); id != "acme" {
+		t.Errorf("TenantFromSubdomain = %q; se esperaba acme", id)
+	}
+	if id, _ := TenantFromSubdomain("example.com")(c); id != "" {
+		t.Errorf("TenantFromSubdomain = %q; se esperaba vacío con varios niveles", id)
+	}
+
+	claims, _ := auth.NewClaims(map[string]any{"tid": "globex", "n": 1})
+	c.SetRequest(c.Request().WithContext(auth.WithClaims(c.Request().Context(), claims)))
+	if id, _ := TenantFromClaim("tid")(c); id != "globex" {
+		t.Errorf("TenantFromClaim = %q; se esperaba globex", id)
+	}
+	if _, err := TenantFromClaim("n")(c); err == nil {
+		t.Error("Se esperaba error con un claim que no es texto")
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// TransactionMiddleware envuelve la petición en una transacción sobre la conexión global (o la del tenant,
+// si TenantMiddleware se ejecuta antes), disponible para los repositorios mediante
+// database.From(c.Request().Context()). La transacción se confirma si el handler responde con un código
+// 2xx y se revierte si devuelve un error o responde con otro código.
+//
+// La respuesta se retiene en memoria hasta confirmar la transacción, de modo que una falla en el Commit
+// se informa al cliente como error en lugar de una respuesta exitosa. Por eso no debe usarse en rutas que
+// transmiten respuestas grandes (ver el paquete export)
+func TransactionMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) (err error) {
+		req := c.Request()
+		ctx := req.Context()
+
+		db, ok := database.DatabaseFrom(ctx)
+		if !ok {
+			db = database.GetDatabase()
+		}
+		if db == nil {
+			return errors.New("no hay una conexión de base de datos inicializada")
+		}
+
+		tx, err := db.BeginTx(ctx)
+		if err != nil {
+			return err
+		}
+
+		res := c.Response()
+		orig := res.Writer
+		buf := &bufferedWriter{ResponseWriter: orig, status: http.StatusOK}
+		res.Writer = buf
+		c.SetRequest(req.WithContext(database.WithTx(ctx, tx)))
+
+		finished := false
+		defer func() {
+			if !finished {
+				_ = tx.Rollback(ctx)
+				discard(res, orig)
+			}
+		}()
+
+		if err = next(c); err != nil {
+			return err
+		}
+
+		if res.Status < http.StatusOK || res.Status >= http.StatusMultipleChoices {
+			finished = true
+			_ = tx.Rollback(ctx)
+			return buf.flush(res, orig)
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		finished = true
+		return buf.flush(res, orig)
+	}
+}
+
+// bufferedWriter retiene el código y el cuerpo de la respuesta hasta que se decida su envío
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush se ignora: la respuesta se envía completa al terminar la transacción
+func (w *bufferedWriter) Flush() {}
+
+// flush envía la respuesta retenida al cliente
+func (w *bufferedWriter) flush(res *echo.Response, orig http.ResponseWriter) error {
+	res.Writer = orig
+	if !res.Committed {
+		return nil
+	}
+
+	orig.WriteHeader(w.status)
+	_, err := orig.Write(w.body.Bytes())
+	return err
+}
+
+// discard descarta la respuesta retenida para que ProblemMiddleware pueda escribir el error
+func discard(res *echo.Response, orig http.ResponseWriter) {
+	res.Writer = orig
+	res.Committed = false
+	res.Status = http.StatusOK
+	res.Size = 0
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/infra/database"
+	"github.com/wfrscltech/vulcano/infra/database/databasetest"
+)
+
+func serve(h echo.HandlerFunc) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(ProblemMiddleware)
+	e.POST("/", h, TransactionMiddleware)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	return rec
+}
+
+// TestTransactionMiddleware_Commit valida que una respuesta 2xx confirme la transacción de la petición
+func TestTransactionMiddleware_Commit(t *testing.T) {
+	db := databasetest.New(t).Install()
+	db.ExpectBegin()
+	db.ExpectExec("INSERT INTO clientes (nombre) VALUES ($1)").WithArgs("Ana").WillReturnResult(1)
+	db.ExpectCommit()
+
+	rec := serve(func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if _, err := database.From(ctx).Exec(ctx, "INSERT INTO clientes (nombre) VALUES ($1)", "Ana"); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, map[string]int{"id": 1})
+	})
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "{\"id\":1}\n" {
+		t.Errorf("Respuesta inesperada: %d %s", rec.Code, rec.Body.String())
+	}
+	if st := db.Statements(); len(st) != 1 || !st[0].InTx {
+		t.Errorf("Se esperaba la sentencia dentro de la transacción: %+v", st)
+	}
+}
+
+// TestTransactionMiddleware_Rollback valida la reversión ante errores y respuestas no exitosas
+func TestTransactionMiddleware_Rollback(t *testing.T) {
+	db := databasetest.New(t).Install()
+	db.ExpectBegin()
+	db.ExpectRollback()
+	db.ExpectBegin()
+	db.ExpectRollback()
+
+	rec := serve(func(c echo.Context) error {
+		_ = c.JSON(http.StatusOK, "parcial")
+		return mistake.New(mistake.Duplicated, "el cliente ya existe", errors.New("duplicado"))
+	})
+	if rec.Code != http.StatusConflict || rec.Body.String() == "\"parcial\"\n" {
+		t.Errorf("Se esperaba solo el error 409, obtuvo: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(func(c echo.Context) error {
+		return c.JSON(http.StatusNotFound, "no existe")
+	})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Se esperaba 404, obtuvo: %d", rec.Code)
+	}
+}
+
+// TestTransactionMiddleware_CommitError valida que una falla en el Commit no se informe como éxito
+func TestTransactionMiddleware_CommitError(t *testing.T) {
+	db := databasetest.New(t).Install()
+	db.ExpectBegin()
+	db.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+
+	rec := serve(func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Se esperaba 500, obtuvo: %d %s", rec.Code, rec.Body.String())
+	}
+}
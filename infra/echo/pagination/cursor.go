@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Cursor representa la posición de una página. Se transmite al cliente codificado en base64 para que sea
+// opaco: en paginación por desplazamiento guarda la cantidad de registros a omitir y en paginación por
+// llave los valores de las columnas de ordenamiento del registro límite
+type Cursor struct {
+	// Registros a omitir (paginación por desplazamiento)
+	Offset int `json:"o,omitempty"`
+	// Valores de las llaves del registro límite (paginación por llave)
+	Keys []any `json:"k,omitempty"`
+	// Indica que la página se obtiene hacia atrás desde Keys (cursor de página anterior)
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode codifica el cursor en un texto opaco apto para URLs
+func (c Cursor) Encode() string {
+	keys := make([]any, len(c.Keys))
+	for i, k := range c.Keys {
+		// Las fechas se normalizan a UTC con precisión completa para no perder registros en el límite
+		if t, ok := k.(time.Time); ok {
+			k = t.UTC().Format(time.RFC3339Nano)
+		}
+		keys[i] = k
+	}
+	c.Keys = keys
+
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodifica un cursor generado por Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+	if c.Offset < 0 {
+		return nil, errors.New("desplazamiento negativo")
+	}
+
+	// Los números se convierten al tipo Go más cercano para que los drivers los envíen correctamente
+	for i, k := range c.Keys {
+		n, ok := k.(json.Number)
+		if !ok {
+			continue
+		}
+		if v, err := n.Int64(); err == nil {
+			c.Keys[i] = v
+		} else if v, err := n.Float64(); err == nil {
+			c.Keys[i] = v
+		}
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,134 @@
+// Package pagination implementa paginación por desplazamiento (page/size) y por llave (keyset/cursor)
+// para cualquiera de los motores soportados, a partir de los parámetros de la petición HTTP.
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/domain/mistake"
+	"github.com/wfrscltech/vulcano/fn"
+)
+
+// Valores por defecto del tamaño de página
+const (
+	DefaultSize = 20
+	MaxSize     = 100
+)
+
+// @Description	Define una página de resultados
+type Page[T any] struct {
+	// Registros de la página
+	Items []T `json:"items"`
+	// Número de página (solo en paginación por desplazamiento)
+	Page int `json:"page,omitempty"     example:"2"`
+	// Tamaño de página solicitado
+	Size int `json:"size"               example:"20"`
+	// Total de registros de la consulta, si se solicitó
+	Total *int64 `json:"total,omitempty" example:"153"`
+	// Cursor opaco de la página siguiente; vacío si no hay más registros
+	Next string `json:"next,omitempty"     example:"eyJvIjo0MH0"`
+	// Cursor opaco de la página anterior; vacío en la primera página
+	Prev string `json:"prev,omitempty"     example:"eyJvIjowfQ"`
+}
+
+// Params contiene los parámetros de paginación de una petición
+type Params struct {
+	// Número de página, base 1
+	Page int
+	// Tamaño de página
+	Size int
+	// Cursor decodificado; nil si la petición no indicó `cursor`
+	Cursor *Cursor
+}
+
+// Offset devuelve la cantidad de registros a omitir
+func (p Params) Offset() int {
+	if p.Cursor != nil && p.Cursor.Keys == nil {
+		return p.Cursor.Offset
+	}
+	return (p.Page - 1) * p.Size
+}
+
+// Option configura la lectura de los parámetros de paginación
+type Option func(*options)
+
+type options struct {
+	defaultSize int
+	maxSize     int
+}
+
+// WithDefaultSize cambia el tamaño de página usado cuando la petición no indica `size`
+func WithDefaultSize(n int) Option {
+	return func(o *options) {
+		o.defaultSize = n
+	}
+}
+
+// WithMaxSize cambia el tamaño de página máximo permitido
+func WithMaxSize(n int) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// Parse lee los parámetros `page`, `size` y `cursor` de la petición. Si se indica `cursor`, este tiene
+// prioridad sobre `page`. Los valores inválidos se devuelven como errores mistake.Invalid
+func Parse(c echo.Context, opts ...Option) (Params, error) {
+	o := options{defaultSize: DefaultSize, maxSize: MaxSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	p := Params{Page: 1, Size: o.defaultSize}
+
+	if v := strings.TrimSpace(c.QueryParam("page")); v != "" {
+		n, err := positive(v)
+		if err != nil {
+			return p, invalid("page", fmt.Sprintf("el parámetro `page` debe ser un entero mayor a 0, se recibió `%s`", v), err)
+		}
+		p.Page = n
+	}
+
+	if v := strings.TrimSpace(c.QueryParam("size")); v != "" {
+		n, err := positive(v)
+		if err != nil {
+			return p, invalid("size", fmt.Sprintf("el parámetro `size` debe ser un entero mayor a 0, se recibió `%s`", v), err)
+		}
+		if n > o.maxSize {
+			return p, invalid("size", fmt.Sprintf("el parámetro `size` no puede ser mayor a %d", o.maxSize), errors.New("tamaño de página excedido"))
+		}
+		p.Size = n
+	}
+
+	if v := strings.TrimSpace(c.QueryParam("cursor")); v != "" {
+		cur, err := DecodeCursor(v)
+		if err != nil {
+			return p, invalid("cursor", "el parámetro `cursor` no es válido", err)
+		}
+		p.Cursor = cur
+	}
+
+	return p, nil
+}
+
+func positive(v string) (int, error) {
+	if !fn.IsNumeric(v) {
+		return 0, errors.New("no es numérico")
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if n < 1 {
+		return 0, errors.New("debe ser mayor a 0")
+	}
+	return n, nil
+}
+
+func invalid(field, msg string, err error) error {
+	return mistake.New(mistake.Invalid, msg, err, field)
+}
@@ -0,0 +1,165 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+type producto struct {
+	ID     int64
+	Nombre string
+}
+
+func scanProducto(row database.Row) (producto, error) {
+	var p producto
+	err := row.Scan(&p.ID, &p.Nombre)
+	return p, err
+}
+
+// productos crea una base SQLite en memoria con 25 productos
+func productos(t *testing.T) database.Database {
+	t.Helper()
+
+	if err := database.New(config.DatabaseConfig{Name: ":memory:", Typo: config.DatabaseTypeSqlite}); err != nil {
+		t.Fatalf("No se esperaba error al conectar, pero obtuvo: %v", err)
+	}
+	db := database.GetDatabase()
+	t.Cleanup(db.Close)
+
+	ctx := context.Background()
+	if _, err := db.Exec(ctx, "CREATE TABLE productos (id INTEGER PRIMARY KEY, nombre TEXT)"); err != nil {
+		t.Fatalf("Error al crear tabla: %v", err)
+	}
+	for i := 1; i <= 25; i++ {
+		if _, err := db.Exec(ctx, "INSERT INTO productos VALUES (?, ?)", i, fmt.Sprintf("P%02d", i)); err != nil {
+			t.Fatalf("Error al insertar: %v", err)
+		}
+	}
+	return db
+}
+
+func params(t *testing.T, target string) Params {
+	t.Helper()
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), httptest.NewRecorder())
+	p, err := Parse(c)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	return p
+}
+
+func ids(items []producto) string {
+	s := ""
+	for _, it := range items {
+		s += fmt.Sprintf("%d,", it.ID)
+	}
+	return s
+}
+
+// TestParse_Failures valida los errores de los parámetros de paginación
+func TestParse_Failures(t *testing.T) {
+	for _, target := range []string{"/?page=0", "/?page=abc", "/?size=-1", "/?size=500", "/?cursor=no-valido!"} {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		c.Request().URL.RawQuery = target[2:]
+		if _, err := Parse(c); err == nil {
+			t.Errorf("%s: se esperaba un error pero no se obtuvo ninguno", target)
+		}
+	}
+}
+
+// TestFetch_Offset valida la paginación por desplazamiento con total y cursores
+func TestFetch_Offset(t *testing.T) {
+	db := productos(t)
+	query := Query[producto]{
+		Typo:    config.DatabaseTypeSqlite,
+		SQL:     "SELECT id, nombre FROM productos WHERE id > ?",
+		Args:    []any{0},
+		OrderBy: []Key{{Column: "id", Desc: true}},
+		Scan:    scanProducto,
+		Total:   true,
+	}
+
+	page, err := Fetch(context.Background(), db, params(t, "/?page=3&size=10"), query)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if ids(page.Items) != "5,4,3,2,1," || page.Page != 3 || *page.Total != 25 || page.Next != "" || page.Prev == "" {
+		t.Fatalf("Página inesperada: %+v", page)
+	}
+
+	prev, err := Fetch(context.Background(), db, params(t, "/?size=10&cursor="+page.Prev), query)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if prev.Page != 2 || ids(prev.Items) != "15,14,13,12,11,10,9,8,7,6," {
+		t.Errorf("Página anterior inesperada: %+v", prev)
+	}
+}
+
+// TestFetch_Keyset valida el recorrido hacia adelante y hacia atrás por llave
+func TestFetch_Keyset(t *testing.T) {
+	db := productos(t)
+	query := Query[producto]{
+		Typo:    config.DatabaseTypeSqlite,
+		SQL:     "SELECT id, nombre FROM productos",
+		OrderBy: []Key{{Column: "nombre"}, {Column: "id"}},
+		Scan:    scanProducto,
+		KeyOf:   func(p producto) []any { return []any{p.Nombre, p.ID} },
+	}
+	ctx := context.Background()
+
+	first, err := Fetch(ctx, db, params(t, "/?size=10"), query)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if ids(first.Items) != "1,2,3,4,5,6,7,8,9,10," || first.Prev != "" || first.Next == "" {
+		t.Fatalf("Primera página inesperada: %+v", first)
+	}
+
+	second, err := Fetch(ctx, db, params(t, "/?size=10&cursor="+first.Next), query)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if ids(second.Items) != "11,12,13,14,15,16,17,18,19,20," || second.Prev == "" || second.Next == "" {
+		t.Fatalf("Segunda página inesperada: %+v", second)
+	}
+
+	back, err := Fetch(ctx, db, params(t, "/?size=10&cursor="+second.Prev), query)
+	if err != nil {
+		t.Fatalf("No se esperaba error, pero obtuvo: %v", err)
+	}
+	if ids(back.Items) != ids(first.Items) || back.Prev != "" || back.Next == "" {
+		t.Errorf("Regreso a la primera página inesperado: %+v", back)
+	}
+
+	if _, err := Fetch(ctx, db, params(t, "/?cursor="+(Cursor{Offset: 10}).Encode()), query); err == nil {
+		t.Error("Se esperaba un error por cursor incompatible")
+	}
+}
+
+// TestKeysetSQL_Dialects valida la consulta generada para SQL Server y PostgreSQL
+func TestKeysetSQL_Dialects(t *testing.T) {
+	keys := []Key{{Column: "fecha", Desc: true}, {Column: "id"}}
+	cur := &Cursor{Keys: []any{"2025-01-01", int64(9)}}
+
+	sql, args := KeysetSQL(config.DatabaseTypeMssql, "SELECT * FROM pedidos WHERE cliente = @p1", []any{7}, keys, cur, 21)
+	want := "SELECT * FROM (SELECT * FROM pedidos WHERE cliente = @p1) AS p WHERE (fecha < @p2) OR (fecha = @p3 AND id > @p4) " +
+		"ORDER BY fecha DESC, id ASC OFFSET 0 ROWS FETCH NEXT 21 ROWS ONLY"
+	if sql != want || len(args) != 4 {
+		t.Errorf("Consulta SQL Server inesperada:\n%s\n%v", sql, args)
+	}
+
+	sql = OffsetSQL(config.DatabaseTypePostgres, "SELECT * FROM pedidos", keys, 21, 40)
+	want = "SELECT * FROM (SELECT * FROM pedidos) AS p ORDER BY fecha DESC, id ASC LIMIT 21 OFFSET 40"
+	if sql != want {
+		t.Errorf("Consulta PostgreSQL inesperada:\n%s", sql)
+	}
+}
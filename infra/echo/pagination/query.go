@@ -0,0 +1,209 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wfrscltech/vulcano/config"
+	"github.com/wfrscltech/vulcano/infra/database"
+)
+
+// columnRe valida los nombres de las columnas de ordenamiento, que se interpolan en la consulta
+var columnRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Key define una columna de ordenamiento de la paginación
+type Key struct {
+	// Nombre de la columna en el resultado de la consulta base
+	Column string
+	// Orden descendente
+	Desc bool
+}
+
+// Query describe una consulta paginable
+type Query[T any] struct {
+	// Tipo de base de datos (config.DatabaseConfig.Typo), define la sintaxis de LIMIT y los marcadores
+	Typo string
+	// Consulta base, sin ORDER BY ni LIMIT; se usa como subconsulta
+	SQL string
+	// Argumentos de la consulta base
+	Args []any
+	// Columnas de ordenamiento; deben identificar de forma única cada registro para la paginación por llave
+	OrderBy []Key
+	// Conversión de cada registro
+	Scan database.ScanFunc[T]
+	// Calcula el total de registros de la consulta base
+	Total bool
+	// Extrae de un registro los valores de las columnas de OrderBy. Si se indica, se usa paginación por
+	// llave en lugar de desplazamiento
+	KeyOf func(T) []any
+}
+
+// Fetch ejecuta la consulta paginada y devuelve la página con sus cursores
+func Fetch[T any](ctx context.Context, q database.Querier, p Params, query Query[T]) (Page[T], error) {
+	page := Page[T]{Items: []T{}, Size: p.Size}
+
+	if err := validateKeys(query.OrderBy); err != nil {
+		return page, err
+	}
+
+	if query.Total {
+		var total int64
+		if err := q.QueryRow(ctx, CountSQL(query.SQL), query.Args...).Scan(&total); err != nil {
+			return page, err
+		}
+		page.Total = &total
+	}
+
+	if query.KeyOf != nil {
+		return fetchKeyset(ctx, q, p, query, page)
+	}
+	return fetchOffset(ctx, q, p, query, page)
+}
+
+func fetchOffset[T any](ctx context.Context, q database.Querier, p Params, query Query[T], page Page[T]) (Page[T], error) {
+	if p.Cursor != nil && p.Cursor.Keys != nil {
+		return page, invalid("cursor", "el parámetro `cursor` no corresponde a esta consulta", errors.New("cursor de llave en paginación por desplazamiento"))
+	}
+
+	offset := p.Offset()
+	sql := OffsetSQL(query.Typo, query.SQL, query.OrderBy, p.Size+1, offset)
+	items, err := database.Collect(database.Stream(ctx, q, query.Scan, sql, query.Args...))
+	if err != nil {
+		return page, err
+	}
+
+	if len(items) > p.Size {
+		items = items[:p.Size]
+		page.Next = Cursor{Offset: offset + p.Size}.Encode()
+	}
+	if offset > 0 {
+		page.Prev = Cursor{Offset: max(0, offset-p.Size)}.Encode()
+	}
+
+	page.Items = append(page.Items, items...)
+	page.Page = offset/p.Size + 1
+	return page, nil
+}
+
+func fetchKeyset[T any](ctx context.Context, q database.Querier, p Params, query Query[T], page Page[T]) (Page[T], error) {
+	cur := p.Cursor
+	if cur != nil && (cur.Keys == nil || len(cur.Keys) != len(query.OrderBy)) {
+		return page, invalid("cursor", "el parámetro `cursor` no corresponde a esta consulta", errors.New("cursor incompatible con las llaves de ordenamiento"))
+	}
+
+	sql, args := KeysetSQL(query.Typo, query.SQL, query.Args, query.OrderBy, cur, p.Size+1)
+	items, err := database.Collect(database.Stream(ctx, q, query.Scan, sql, args...))
+	if err != nil {
+		return page, err
+	}
+
+	more := len(items) > p.Size
+	if more {
+		items = items[:p.Size]
+	}
+
+	backward := cur != nil && cur.Backward
+	if backward {
+		slices.Reverse(items)
+	}
+
+	if len(items) > 0 {
+		first, last := query.KeyOf(items[0]), query.KeyOf(items[len(items)-1])
+		// Hacia adelante hay página siguiente si sobró un registro; hacia atrás siempre la hay
+		if (!backward && more) || backward {
+			page.Next = Cursor{Keys: last}.Encode()
+		}
+		// Hay página anterior si se llegó desde un cursor o, hacia atrás, si sobró un registro
+		if (!backward && cur != nil) || (backward && more) {
+			page.Prev = Cursor{Keys: first, Backward: true}.Encode()
+		}
+	}
+
+	page.Items = append(page.Items, items...)
+	return page, nil
+}
+
+// CountSQL devuelve la consulta que cuenta los registros de la consulta base
+func CountSQL(base string) string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS p", base)
+}
+
+// OffsetSQL decora la consulta base con el ordenamiento y el límite por desplazamiento del dialecto:
+// `LIMIT ... OFFSET ...` o, en SQL Server, `OFFSET ... ROWS FETCH NEXT ... ROWS ONLY`
+func OffsetSQL(typo, base string, orderBy []Key, limit, offset int) string {
+	return fmt.Sprintf("SELECT * FROM (%s) AS p ORDER BY %s %s", base, orderClause(orderBy, false), limitClause(typo, limit, offset))
+}
+
+// KeysetSQL decora la consulta base con la condición de llave del cursor, el ordenamiento y el límite.
+// Los valores del cursor se agregan a continuación de `args`, con los marcadores del dialecto
+func KeysetSQL(typo, base string, args []any, orderBy []Key, cur *Cursor, limit int) (string, []any) {
+	backward := cur != nil && cur.Backward
+	args = slices.Clone(args)
+
+	var where string
+	if cur != nil {
+		var or []string
+		for i := range orderBy {
+			var and []string
+			for j := 0; j <= i; j++ {
+				op := "="
+				if j == i {
+					op = comparison(orderBy[j].Desc, backward)
+				}
+				args = append(args, cur.Keys[j])
+				and = append(and, fmt.Sprintf("%s %s %s", orderBy[j].Column, op, database.Placeholder(typo, len(args))))
+			}
+			or = append(or, "("+strings.Join(and, " AND ")+")")
+		}
+		where = " WHERE " + strings.Join(or, " OR ")
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM (%s) AS p%s ORDER BY %s %s", base, where, orderClause(orderBy, backward), limitClause(typo, limit, 0))
+	return sql, args
+}
+
+// comparison devuelve el operador que selecciona los registros posteriores al cursor
+func comparison(desc, backward bool) string {
+	if desc != backward {
+		return "<"
+	}
+	return ">"
+}
+
+func orderClause(keys []Key, reverse bool) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		dir := "ASC"
+		if k.Desc != reverse {
+			dir = "DESC"
+		}
+		parts[i] = k.Column + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+func limitClause(typo string, limit, offset int) string {
+	if typo == config.DatabaseTypeMssql {
+		return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+	}
+	if offset > 0 {
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	}
+	return fmt.Sprintf("LIMIT %d", limit)
+}
+
+func validateKeys(keys []Key) error {
+	if len(keys) == 0 {
+		return errors.New("paginación: se requiere al menos una columna de ordenamiento")
+	}
+	for _, k := range keys {
+		if !columnRe.MatchString(k.Column) {
+			return fmt.Errorf("paginación: el nombre de columna `%s` no es válido", k.Column)
+		}
+	}
+	return nil
+}
@@ -29,5 +29,5 @@ func Init(level slog.Level, logname, dir string) {
 		out = io.MultiWriter(os.Stdout, rotator)
 	}
 	handler := slog.NewJSONHandler(out, &slog.HandlerOptions{Level: level})
-	Log = slog.New(handler)
+	Log = slog.New(NewContextHandler(handler))
 }
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Plazo con que Group apaga los demás servicios cuando uno falla
+const groupShutdownTimeout = 5 * time.Second
+
+// Group ejecuta varios servicios como uno solo, por ejemplo el servidor HTTP junto a los procesos en
+// segundo plano:
+//
+//	service.RunGracefully(log, service.Group{server, relay, workers})
+type Group []Runner
+
+// Start inicia todos los servicios en paralelo y espera a que terminen. Si uno falla (ej. el puerto del
+// servidor HTTP ya está en uso) apaga los demás y devuelve su error sin esperar al resto. El cierre normal
+// del servidor HTTP (http.ErrServerClosed) no se considera una falla
+func (g Group) Start() error {
+	errs := make(chan error, len(g))
+	for _, r := range g {
+		go func() {
+			err := r.Start()
+			if errors.Is(err, http.ErrServerClosed) {
+				err = nil
+			}
+			errs <- err
+		}()
+	}
+
+	for range g {
+		if err := <-errs; err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), groupShutdownTimeout)
+			defer cancel()
+			return errors.Join(err, g.Shutdown(ctx))
+		}
+	}
+	return nil
+}
+
+// Shutdown apaga todos los servicios en paralelo con el mismo plazo
+func (g Group) Shutdown(ctx context.Context) error {
+	errs := make([]error, len(g))
+
+	var wg sync.WaitGroup
+	for i, r := range g {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = r.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
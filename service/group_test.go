@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeRunner bloquea Start hasta que se llama Shutdown o devuelve `err` de inmediato
+type fakeRunner struct {
+	err  error
+	stop chan struct{}
+}
+
+func newFakeRunner(err error) *fakeRunner {
+	return &fakeRunner{err: err, stop: make(chan struct{})}
+}
+
+func (f *fakeRunner) Start() error {
+	if f.err != nil {
+		return f.err
+	}
+	<-f.stop
+	return http.ErrServerClosed
+}
+
+func (f *fakeRunner) Shutdown(context.Context) error {
+	select {
+	case <-f.stop:
+	default:
+		close(f.stop)
+	}
+	return nil
+}
+
+// TestGroup_StartFails valida que la falla de un servicio apague los demás y se devuelva de inmediato
+func TestGroup_StartFails(t *testing.T) {
+	boom := errors.New("puerto en uso")
+	server, worker := newFakeRunner(boom), newFakeRunner(nil)
+
+	done := make(chan error, 1)
+	go func() { done <- Group{server, worker}.Start() }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, boom) {
+			t.Errorf("Se esperaba %v, obtuvo: %v", boom, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Se esperaba que Start termine al fallar un servicio")
+	}
+
+	select {
+	case <-worker.stop:
+	default:
+		t.Error("Se esperaba que se apaguen los demás servicios")
+	}
+}
+
+// TestGroup_Shutdown valida que el cierre normal no se informe como error
+func TestGroup_Shutdown(t *testing.T) {
+	g := Group{newFakeRunner(nil), newFakeRunner(nil)}
+
+	done := make(chan error, 1)
+	go func() { done <- g.Start() }()
+
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Fatalf("No se esperaba error al apagar, pero obtuvo: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("No se esperaba error de Start, pero obtuvo: %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,19 +17,28 @@ type Runner interface {
 }
 
 // RunGracefully inicia un servicio y lo mantiene en ejecución hasta recibir una señal de
-// apagado.
+// apagado. Si el servicio no logra iniciar (ej. el puerto ya está en uso) lo apaga y devuelve el error
+// sin esperar la señal.
 func RunGracefully(log *slog.Logger, srv Runner) error {
+	failed := make(chan error, 1)
 	go func() {
-		if err := srv.Start(); err != nil {
+		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error("Error al iniciar servicio", "error", err)
+			failed <- err
 		}
 	}()
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
-	log.Info("Se recibe señal de apagado")
+	defer signal.Stop(quit)
+
+	var err error
+	select {
+	case <-quit:
+		log.Info("Se recibe señal de apagado")
+	case err = <-failed:
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return srv.Shutdown(ctx)
+	return errors.Join(err, srv.Shutdown(ctx))
 }